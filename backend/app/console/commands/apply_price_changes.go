@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goravel/framework/contracts/console"
+	"github.com/goravel/framework/contracts/console/command"
+
+	"goravel/app/services"
+)
+
+type ApplyPriceChanges struct {
+}
+
+// Signature The name and signature of the console command.
+func (receiver *ApplyPriceChanges) Signature() string {
+	return "products:apply-price-changes"
+}
+
+// Description The console command description.
+func (receiver *ApplyPriceChanges) Description() string {
+	return "Apply scheduled product prices whose effective time has passed"
+}
+
+// Extend The console command extend.
+func (receiver *ApplyPriceChanges) Extend() command.Extend {
+	return command.Extend{Category: "products"}
+}
+
+// Handle Execute the console command.
+func (receiver *ApplyPriceChanges) Handle(ctx console.Context) error {
+	applied, err := services.ApplyDuePriceChanges(time.Now())
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		ctx.Info("No price changes due")
+		return nil
+	}
+
+	for _, change := range applied {
+		ctx.Info(fmt.Sprintf("Product #%d is now priced at %.2f", change.ProductID, change.Price))
+	}
+
+	return nil
+}
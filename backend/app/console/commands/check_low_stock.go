@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goravel/framework/contracts/console"
+	"github.com/goravel/framework/contracts/console/command"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/services"
+)
+
+type CheckLowStock struct {
+}
+
+// Signature The name and signature of the console command.
+func (receiver *CheckLowStock) Signature() string {
+	return "inventory:check-low-stock"
+}
+
+// Description The console command description.
+func (receiver *CheckLowStock) Description() string {
+	return "Raise alerts for ingredients below their stock threshold"
+}
+
+// Extend The console command extend.
+func (receiver *CheckLowStock) Extend() command.Extend {
+	return command.Extend{Category: "inventory"}
+}
+
+// Handle Execute the console command.
+func (receiver *CheckLowStock) Handle(ctx console.Context) error {
+	raised, err := services.CheckLowStock()
+	if err != nil {
+		return err
+	}
+	if len(raised) == 0 {
+		ctx.Info("No new low stock alerts")
+		return nil
+	}
+
+	var body strings.Builder
+	body.WriteString("<p>The following ingredients are below their stock threshold:</p><ul>")
+	for _, notification := range raised {
+		body.WriteString(fmt.Sprintf("<li>%s</li>", notification.Message))
+	}
+	body.WriteString("</ul>")
+
+	// Alerts are already stored and listed on the notifications endpoint, so a mail failure is only logged
+	if err := services.NotifyAdmins(fmt.Sprintf("[Inventory] %d ingredient(s) low on stock", len(raised)), body.String()); err != nil {
+		facades.Log().Errorf("low stock mail error: %v", err)
+	}
+
+	ctx.Info(fmt.Sprintf("Raised %d low stock alert(s)", len(raised)))
+
+	return nil
+}
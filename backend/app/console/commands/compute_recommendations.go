@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goravel/framework/contracts/console"
+	"github.com/goravel/framework/contracts/console/command"
+
+	"goravel/app/services"
+)
+
+type ComputeRecommendations struct {
+}
+
+// Signature The name and signature of the console command.
+func (receiver *ComputeRecommendations) Signature() string {
+	return "products:compute-recommendations"
+}
+
+// Description The console command description.
+func (receiver *ComputeRecommendations) Description() string {
+	return "Rebuild frequently bought together recommendations from completed orders"
+}
+
+// Extend The console command extend.
+func (receiver *ComputeRecommendations) Extend() command.Extend {
+	return command.Extend{
+		Category: "products",
+		Flags: []command.Flag{
+			&command.IntFlag{
+				Name:  "days",
+				Value: 90,
+				Usage: "Days of order history to learn from",
+			},
+			&command.IntFlag{
+				Name:  "min-orders",
+				Value: 3,
+				Usage: "Orders a pair of products must share to be recommended",
+			},
+			&command.IntFlag{
+				Name:  "limit",
+				Value: 10,
+				Usage: "Recommendations kept per product",
+			},
+		},
+	}
+}
+
+// Handle Execute the console command.
+func (receiver *ComputeRecommendations) Handle(ctx console.Context) error {
+	products, err := services.ComputeRecommendations(ctx.OptionInt("days"), ctx.OptionInt("min-orders"), ctx.OptionInt("limit"), time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx.Info(fmt.Sprintf("Computed recommendations for %d product(s)", products))
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goravel/framework/contracts/console"
+	"github.com/goravel/framework/contracts/console/command"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type ForecastDemand struct {
+}
+
+// Signature The name and signature of the console command.
+func (receiver *ForecastDemand) Signature() string {
+	return "inventory:forecast-demand"
+}
+
+// Description The console command description.
+func (receiver *ForecastDemand) Description() string {
+	return "Forecast ingredient usage and suggest reorder quantities"
+}
+
+// Extend The console command extend.
+func (receiver *ForecastDemand) Extend() command.Extend {
+	return command.Extend{
+		Category: "inventory",
+		Flags: []command.Flag{
+			&command.IntFlag{
+				Name:  "days",
+				Value: 7,
+				Usage: "Number of days to forecast",
+			},
+			&command.IntFlag{
+				Name:  "weeks",
+				Value: 8,
+				Usage: "Weeks of order history to learn from",
+			},
+		},
+	}
+}
+
+// Handle Execute the console command.
+func (receiver *ForecastDemand) Handle(ctx console.Context) error {
+	days := ctx.OptionInt("days")
+	forecast, err := services.ForecastDemand(days, ctx.OptionInt("weeks"))
+	if err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	var reorders int
+	var totalCost float64
+	body.WriteString(fmt.Sprintf("<p>Suggested reorders to cover the next %d day(s):</p><ul>", days))
+	for _, ingredient := range forecast.Ingredients {
+		if ingredient.ReorderQuantity <= 0 {
+			continue
+		}
+		reorders++
+		totalCost += ingredient.ReorderCost
+		body.WriteString(fmt.Sprintf("<li>%s: order %g %s (in stock %g, expected usage %g)</li>",
+			ingredient.Name, ingredient.ReorderQuantity, ingredient.Unit, ingredient.Quantity, ingredient.ExpectedUsage))
+	}
+	body.WriteString(fmt.Sprintf("</ul><p>Estimated cost: %.2f</p>", totalCost))
+
+	if reorders == 0 {
+		ctx.Info("Stock covers the forecast, nothing to reorder")
+		return nil
+	}
+
+	notification := models.AdminNotifications{
+		Type:    services.NotificationReorder,
+		Title:   fmt.Sprintf("%d ingredient(s) to reorder", reorders),
+		Message: fmt.Sprintf("Forecast for the next %d day(s) suggests reordering %d ingredient(s) for about %.2f.", days, reorders, totalCost),
+	}
+	if err := facades.Orm().Query().Create(&notification); err != nil {
+		return err
+	}
+
+	if err := services.NotifyAdmins(fmt.Sprintf("[Inventory] %d ingredient(s) to reorder", reorders), body.String()); err != nil {
+		facades.Log().Errorf("reorder suggestion mail error: %v", err)
+	}
+
+	ctx.Info(fmt.Sprintf("Suggested %d reorder(s)", reorders))
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/goravel/framework/contracts/console"
+	"github.com/goravel/framework/contracts/console/command"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type NormalizeUnits struct {
+}
+
+// Signature The name and signature of the console command.
+func (receiver *NormalizeUnits) Signature() string {
+	return "inventory:normalize-units"
+}
+
+// Description The console command description.
+func (receiver *NormalizeUnits) Description() string {
+	return "Rewrite ingredient units to registry codes and list unknown units"
+}
+
+// Extend The console command extend.
+func (receiver *NormalizeUnits) Extend() command.Extend {
+	return command.Extend{Category: "inventory"}
+}
+
+// Handle Execute the console command.
+func (receiver *NormalizeUnits) Handle(ctx console.Context) error {
+	var ingredients []models.Ingredients
+	if err := facades.Orm().Query().Find(&ingredients); err != nil {
+		return err
+	}
+
+	var updated int
+	for _, ingredient := range ingredients {
+		unit, err := services.NormalizeUnit(ingredient.Unit)
+		if err != nil {
+			ctx.Warning(fmt.Sprintf("%s (#%d) has unknown unit %q, set it through the ingredient API", ingredient.Name, ingredient.ID, ingredient.Unit))
+			continue
+		}
+		if unit == ingredient.Unit {
+			continue
+		}
+
+		// Only the spelling changes, the unit itself stays the same
+		if _, err := facades.Orm().Query().Model(&models.Ingredients{}).Where("id = ?", ingredient.ID).Update("unit", unit); err != nil {
+			return err
+		}
+		updated++
+	}
+
+	ctx.Info(fmt.Sprintf("Normalised the unit of %d ingredient(s)", updated))
+
+	return nil
+}
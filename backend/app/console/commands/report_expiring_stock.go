@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goravel/framework/contracts/console"
+	"github.com/goravel/framework/contracts/console/command"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type ReportExpiringStock struct {
+}
+
+// Signature The name and signature of the console command.
+func (receiver *ReportExpiringStock) Signature() string {
+	return "inventory:report-expiring"
+}
+
+// Description The console command description.
+func (receiver *ReportExpiringStock) Description() string {
+	return "Report ingredient batches that are expired or about to expire"
+}
+
+// Extend The console command extend.
+func (receiver *ReportExpiringStock) Extend() command.Extend {
+	return command.Extend{
+		Category: "inventory",
+		Flags: []command.Flag{
+			&command.IntFlag{
+				Name:  "days",
+				Value: 3,
+				Usage: "Include batches expiring within this many days",
+			},
+		},
+	}
+}
+
+// Handle Execute the console command.
+func (receiver *ReportExpiringStock) Handle(ctx console.Context) error {
+	days := ctx.OptionInt("days")
+	batches, err := services.ExpiringBatches(days)
+	if err != nil {
+		return err
+	}
+	if len(batches) == 0 {
+		ctx.Info("No batches expiring soon")
+		return nil
+	}
+
+	var body strings.Builder
+	var totalValue float64
+	body.WriteString(fmt.Sprintf("<p>The following batches are expired or expire within %d day(s):</p><ul>", days))
+	for _, batch := range batches {
+		value := batch.Remaining * batch.UnitCost
+		totalValue += value
+		body.WriteString(fmt.Sprintf("<li>%s: %g %s from batch #%d, expires %s (value %.2f)</li>",
+			batch.Ingredient.Name, batch.Remaining, batch.Ingredient.Unit, batch.ID, batch.ExpiresAt.Format("2006-01-02"), value))
+	}
+	body.WriteString(fmt.Sprintf("</ul><p>Total value at risk: %.2f</p>", totalValue))
+
+	notification := models.AdminNotifications{
+		Type:    services.NotificationExpiringStock,
+		Title:   fmt.Sprintf("%d batch(es) expiring soon", len(batches)),
+		Message: fmt.Sprintf("%d ingredient batch(es) worth %.2f are expired or expire within %d day(s).", len(batches), totalValue, days),
+	}
+	if err := facades.Orm().Query().Create(&notification); err != nil {
+		return err
+	}
+
+	if err := services.NotifyAdmins(fmt.Sprintf("[Inventory] %d batch(es) expiring soon", len(batches)), body.String()); err != nil {
+		facades.Log().Errorf("expiring stock mail error: %v", err)
+	}
+
+	ctx.Info(fmt.Sprintf("Reported %d expiring batch(es)", len(batches)))
+
+	return nil
+}
@@ -3,15 +3,31 @@ package console
 import (
 	"github.com/goravel/framework/contracts/console"
 	"github.com/goravel/framework/contracts/schedule"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/console/commands"
 )
 
 type Kernel struct {
 }
 
 func (kernel Kernel) Schedule() []schedule.Event {
-	return []schedule.Event{}
+	return []schedule.Event{
+		facades.Schedule().Command("inventory:check-low-stock").EveryFifteenMinutes().SkipIfStillRunning(),
+		facades.Schedule().Command("inventory:report-expiring").DailyAt("07:00"),
+		facades.Schedule().Command("inventory:forecast-demand").DailyAt("06:00").SkipIfStillRunning(),
+		facades.Schedule().Command("products:apply-price-changes").EveryMinute().SkipIfStillRunning(),
+		facades.Schedule().Command("products:compute-recommendations").DailyAt("03:00").SkipIfStillRunning(),
+	}
 }
 
 func (kernel Kernel) Commands() []console.Command {
-	return []console.Command{}
+	return []console.Command{
+		&commands.CheckLowStock{},
+		&commands.ReportExpiringStock{},
+		&commands.NormalizeUnits{},
+		&commands.ForecastDemand{},
+		&commands.ApplyPriceChanges{},
+		&commands.ComputeRecommendations{},
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"goravel/app/models"
+	"goravel/app/services"
 	"strconv"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -165,15 +166,46 @@ func (auth *AuthController) Login(ctx http.Context) http.Response {
 		})
 	}
 
+	// A cart built before signing in joins the user's cart. An expired token
+	// or a failed merge leaves the guest cart alone and does not block login.
+	cartMerged := false
+	if cartToken := ctx.Request().Header("X-Cart-Token"); cartToken != "" {
+		if guestCart, err := services.GuestCartFromToken(cartToken); err == nil {
+			cartMerged = mergeGuestCart(guestCart.ID, user.ID)
+		}
+	}
+
 	return ctx.Response().Json(200, map[string]interface{}{
 		"message":      "Login successful",
 		"token":        tokenString,
 		"refreshToken": refreshTokenString,
 		"role":         user.Role,
 		"user":         user,
+		"cart_merged":  cartMerged,
 	})
 }
 
+// mergeGuestCart moves a guest cart into the user's cart in one transaction
+// and reports whether it happened.
+func mergeGuestCart(guestCartID, userID int64) bool {
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		facades.Log().Errorf("guest cart merge error: %v", err)
+		return false
+	}
+	if err := services.MergeGuestCart(tx, guestCartID, userID); err != nil {
+		tx.Rollback()
+		facades.Log().Errorf("guest cart merge error: %v", err)
+		return false
+	}
+	if err := tx.Commit(); err != nil {
+		facades.Log().Errorf("guest cart merge error: %v", err)
+		return false
+	}
+
+	return true
+}
+
 // POST /logout
 func (c *AuthController) Logout(ctx http.Context) http.Response {
 	authHeader := ctx.Request().Header("Authorization")
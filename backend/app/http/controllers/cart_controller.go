@@ -1,12 +1,16 @@
 package controllers
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/goravel/framework/contracts/database/orm"
 	"github.com/goravel/framework/contracts/http"
 
+	"goravel/app/http/utils"
 	"goravel/app/models"
+	"goravel/app/services"
 
 	"strconv"
 
@@ -56,24 +60,123 @@ func getUserIDFromRequest(ctx http.Context) (int64, error) {
 	return 0, nil
 }
 
-// InitCart - Khởi tạo hoặc lấy cart active của user (gọi khi login)
-func (c *CartController) InitCart(ctx http.Context) http.Response {
-	// Get user_id from JWT token
-	userID, err := getUserIDFromRequest(ctx)
-	if err != nil || userID == 0 {
+// cartOwner is who a cart request acts for: a signed in user, or a guest
+// holding the token of the cart they started.
+type cartOwner struct {
+	UserID    int64
+	GuestCart models.Carts
+}
+
+// errCartUnauthorized is returned by cartOwnerFromRequest for a bearer token
+// that names no user. Other errors are failures to look the cart up.
+var errCartUnauthorized = errors.New("user_id not found")
+
+// cartOwnerFromRequest reads the owner from the bearer token, or else from the
+// X-Cart-Token header. A guest who has not added anything yet has neither, nor
+// has one whose token expired or whose cart was merged on login; they start a
+// new cart and get a fresh token.
+func cartOwnerFromRequest(ctx http.Context) (cartOwner, error) {
+	if ctx.Request().Header("Authorization") != "" {
+		userID, err := getUserIDFromRequest(ctx)
+		if err != nil || userID == 0 {
+			return cartOwner{}, errCartUnauthorized
+		}
+		return cartOwner{UserID: userID}, nil
+	}
+	if token := ctx.Request().Header("X-Cart-Token"); token != "" {
+		cart, err := services.GuestCartFromToken(token)
+		if errors.Is(err, services.ErrInvalidCartToken) {
+			return cartOwner{}, nil
+		}
+		return cartOwner{GuestCart: cart}, err
+	}
+
+	return cartOwner{}, nil
+}
+
+// activeCart returns the owner's active cart, with a zero ID when there is none.
+func (owner cartOwner) activeCart() (models.Carts, error) {
+	if owner.UserID == 0 {
+		return owner.GuestCart, nil
+	}
+	var cart models.Carts
+	err := facades.Orm().Query().Where("user_id = ? AND status = ?", owner.UserID, "active").First(&cart)
+
+	return cart, err
+}
+
+// newCart creates an active cart for the owner, without a user for guests.
+func (owner cartOwner) newCart(tx orm.Query) (models.Carts, error) {
+	cart := models.Carts{
+		Status: "active",
+	}
+	if owner.UserID != 0 {
+		cart.UserID = &owner.UserID
+	}
+	err := tx.Create(&cart)
+
+	return cart, err
+}
+
+// withCartToken adds the signed token of a guest cart to a response body, the
+// client sends it back in X-Cart-Token. User carts need no token.
+func withCartToken(body map[string]interface{}, cart models.Carts) (map[string]interface{}, error) {
+	if cart.UserID != nil || cart.ID == 0 {
+		return body, nil
+	}
+	token, err := services.IssueGuestCartToken(cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	body["cart_token"] = token
+
+	return body, nil
+}
+
+// cartOwnerFailed answers a request whose cart owner could not be read.
+func cartOwnerFailed(ctx http.Context, err error) http.Response {
+	if errors.Is(err, errCartUnauthorized) {
 		return ctx.Response().Json(401, map[string]interface{}{
 			"message": "Unauthorized - user_id not found",
 		})
 	}
 
+	// Not the client's fault, it should keep its cart token and retry
+	return ctx.Response().Json(500, map[string]interface{}{
+		"message": "Internal server error",
+		"error":   err.Error(),
+	})
+}
+
+// InitCart - Khởi tạo hoặc lấy cart active của user (gọi khi login), khách
+// chưa đăng nhập nhận cart_token để gửi lại qua header X-Cart-Token
+func (c *CartController) InitCart(ctx http.Context) http.Response {
+	owner, err := cartOwnerFromRequest(ctx)
+	if err != nil {
+		return cartOwnerFailed(ctx, err)
+	}
+
 	// Check if user already has an active cart
-	var cart models.Carts
-	queryErr := facades.Orm().Query().Where("user_id = ? AND status = ?", userID, "active").First(&cart)
+	cart, err := owner.activeCart()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
 
-	if queryErr == nil {
+	if cart.ID != 0 {
 		// Cart exists, get cart items
 		var cartItems []models.CartItem
-		if err := facades.Orm().Query().Where("cart_id = ?", cart.ID).With("Product").Find(&cartItems); err != nil {
+		if err := facades.Orm().Query().Where("cart_id = ? AND cart_combo_id IS NULL", cart.ID).With("Product").With("Variant").With("Modifiers.Option").Find(&cartItems); err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+
+		cartCombos, err := services.LoadCartCombos(cart.ID)
+		if err != nil {
 			return ctx.Response().Json(500, map[string]interface{}{
 				"message": "Internal server error",
 				"error":   err.Error(),
@@ -83,48 +186,68 @@ func (c *CartController) InitCart(ctx http.Context) http.Response {
 		// Calculate total
 		var total float64
 		for _, item := range cartItems {
-			total += item.Product.Price * float64(item.Quantity)
+			total += services.CartItemUnitPrice(item) * float64(item.Quantity)
+		}
+		for _, cartCombo := range cartCombos {
+			total += cartCombo.UnitPrice * float64(cartCombo.Quantity)
 		}
 
-		return ctx.Response().Json(200, map[string]interface{}{
+		body, err := withCartToken(map[string]interface{}{
 			"message": "Cart retrieved successfully",
 			"data": map[string]interface{}{
 				"cart":   cart,
 				"items":  cartItems,
-				"length": len(cartItems),
+				"combos": cartCombos,
+				"length": len(cartItems) + len(cartCombos),
 				"total":  total,
 			},
-		})
+		}, cart)
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+
+		return ctx.Response().Json(200, body)
 	}
 
 	// No active cart found, create new one
-	newCart := models.Carts{
-		UserID: userID,
-		Status: "active",
-	}
-	if err := facades.Orm().Query().Create(&newCart); err != nil {
+	newCart, err := owner.newCart(facades.Orm().Query())
+	if err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
 			"error":   err.Error(),
 		})
 	}
 
-	return ctx.Response().Json(200, map[string]interface{}{
+	body, err := withCartToken(map[string]interface{}{
 		"message": "Cart created successfully",
 		"data": map[string]interface{}{
 			"cart":   newCart,
 			"items":  []models.CartItem{},
+			"combos": []models.CartCombos{},
 			"length": 0,
 			"total":  0,
 		},
-	})
+	}, newCart)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, body)
 }
 
 func (c *CartController) AddItemToCart(ctx http.Context) http.Response {
 	// Bind JSON request body
 	type AddItemRequest struct {
-		ProductID int64 `json:"product_id"`
-		Quantity  int   `json:"quantity"`
+		ProductID         int64   `json:"product_id"`
+		VariantID         *int64  `json:"variant_id"`
+		ModifierOptionIDs []int64 `json:"modifier_option_ids"`
+		Quantity          int     `json:"quantity"`
 	}
 
 	var req AddItemRequest
@@ -147,12 +270,9 @@ func (c *CartController) AddItemToCart(ctx http.Context) http.Response {
 		})
 	}
 
-	// Get user_id from JWT token
-	userID, err := getUserIDFromRequest(ctx)
-	if err != nil || userID == 0 {
-		return ctx.Response().Json(401, map[string]interface{}{
-			"message": "Unauthorized - user_id not found",
-		})
+	owner, err := cartOwnerFromRequest(ctx)
+	if err != nil {
+		return cartOwnerFailed(ctx, err)
 	}
 
 	productID := req.ProductID
@@ -166,33 +286,118 @@ func (c *CartController) AddItemToCart(ctx http.Context) http.Response {
 		})
 	}
 
-	tx, err := facades.Orm().Query().Begin()
+	// Breakfast, lunch and late-night items can only be ordered during their schedule
+	onSchedule, err := services.ProductOnSchedule(productID, services.RestaurantNow())
 	if err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
 			"error":   err.Error(),
 		})
 	}
+	if !onSchedule {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Sản phẩm không phục vụ vào khung giờ này",
+		})
+	}
 
-	// Find or create active cart for user
-	var cart models.Carts
-	if err := facades.Orm().Query().Where("user_id = ? AND status = ?", userID, "active").First(&cart); err != nil {
-		cart = models.Carts{
-			UserID: userID,
-			Status: "active",
+	// Products in a category that was switched off are off the menu
+	hidden, err := services.HiddenCategoryProductIDs([]int64{productID})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if hidden[productID] {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Sản phẩm không còn bán",
+		})
+	}
+
+	// Products sold in sizes must be added with one of their variants
+	variant, err := services.ResolveVariant(productID, req.VariantID)
+	if err != nil {
+		if errors.Is(err, services.ErrVariantRequired) || errors.Is(err, services.ErrVariantNotFound) {
+			return ctx.Response().Json(422, map[string]interface{}{
+				"message": "Vui lòng chọn kích cỡ hợp lệ cho sản phẩm",
+				"error":   err.Error(),
+			})
 		}
-		if err2 := facades.Orm().Query().Create(&cart); err2 != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	// Toppings, spice level and extras must follow the rules of their groups
+	modifiers, err := services.ResolveModifiers(productID, req.ModifierOptionIDs)
+	if err != nil {
+		var selectionErr *services.ModifierSelectionError
+		if errors.As(err, &selectionErr) {
+			return ctx.Response().Json(422, map[string]interface{}{
+				"message":  "Lựa chọn thêm cho sản phẩm không hợp lệ",
+				"error":    selectionErr.Error(),
+				"group_id": selectionErr.GroupID,
+			})
+		}
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	// Find or create active cart for user, or a guest cart for visitors
+	cart, err := owner.activeCart()
+	if err != nil || cart.ID == 0 {
+		if cart, err = owner.newCart(tx); err != nil {
 			tx.Rollback()
 			return ctx.Response().Json(500, map[string]interface{}{
 				"message": "Internal server error",
-				"error":   err2.Error(),
+				"error":   err.Error(),
 			})
 		}
 	}
 
-	// Check if item already exists in cart
+	// Check if item already exists in cart (same product, size and modifiers)
 	var existingItem models.CartItem
-	facades.Orm().Query().Where("cart_id = ? AND product_id = ?", cart.ID, productID).First(&existingItem)
+	var candidates []models.CartItem
+	existingQuery := facades.Orm().Query().Where("cart_id = ? AND product_id = ? AND cart_combo_id IS NULL", cart.ID, productID)
+	if variant != nil {
+		existingQuery = existingQuery.Where("variant_id = ?", variant.ID)
+	} else {
+		existingQuery = existingQuery.Where("variant_id IS NULL")
+	}
+	existingQuery.With("Modifiers").Find(&candidates)
+	for _, candidate := range candidates {
+		if services.SameModifiers(candidate, modifiers) {
+			existingItem = candidate
+			break
+		}
+	}
+
+	// Reject the item if the kitchen cannot make the resulting quantity
+	canMake, err := services.CanMakeProduct(productID, float64(existingItem.Quantity+quantity)*services.RecipeFactor(variant))
+	if err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if !canMake {
+		tx.Rollback()
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Sản phẩm đã hết nguyên liệu",
+		})
+	}
 
 	// If item exists (ID > 0), update quantity
 	if existingItem.ID > 0 {
@@ -211,10 +416,17 @@ func (c *CartController) AddItemToCart(ctx http.Context) http.Response {
 				"error":   err.Error(),
 			})
 		}
-		return ctx.Response().Json(200, map[string]interface{}{
+		body, err := withCartToken(map[string]interface{}{
 			"message": "Cart item quantity updated",
 			"data":    existingItem,
-		})
+		}, cart)
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		return ctx.Response().Json(200, body)
 	}
 
 	// Create new cart item
@@ -223,6 +435,9 @@ func (c *CartController) AddItemToCart(ctx http.Context) http.Response {
 		ProductID: productID,
 		Quantity:  quantity,
 	}
+	if variant != nil {
+		cartItem.VariantID = &variant.ID
+	}
 
 	if err := tx.Create(&cartItem); err != nil {
 		tx.Rollback()
@@ -232,6 +447,22 @@ func (c *CartController) AddItemToCart(ctx http.Context) http.Response {
 		})
 	}
 
+	for _, option := range modifiers {
+		cartItemModifier := models.CartItemModifiers{
+			CartItemID:       cartItem.ID,
+			ModifierOptionID: option.ID,
+		}
+		if err := tx.Create(&cartItemModifier); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		cartItemModifier.Option = option
+		cartItem.Modifiers = append(cartItem.Modifiers, cartItemModifier)
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
@@ -240,30 +471,36 @@ func (c *CartController) AddItemToCart(ctx http.Context) http.Response {
 		})
 	}
 
-	return ctx.Response().Json(200, map[string]interface{}{
+	body, err := withCartToken(map[string]interface{}{
 		"message": "Item added to cart successfully",
 		"data":    cartItem,
-	})
+	}, cart)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, body)
 }
 
 // Lấy list item trong giỏ hàng theo user_id từ JWT token
 func (c *CartController) GetCartByUserID(ctx http.Context) http.Response {
-	// Get user_id from JWT token
-	userID, err := getUserIDFromRequest(ctx)
-	if err != nil || userID == 0 {
-		return ctx.Response().Json(401, map[string]interface{}{
-			"message": "Unauthorized - user_id not found",
-		})
+	owner, err := cartOwnerFromRequest(ctx)
+	if err != nil {
+		return cartOwnerFailed(ctx, err)
 	}
 
-	var cart models.Carts
-	if err := facades.Orm().Query().Where("user_id = ? AND status = ?", userID, "active").First(&cart); err != nil {
+	cart, err := owner.activeCart()
+	if err != nil || cart.ID == 0 {
 		// Return empty cart if not found
 		return ctx.Response().Json(200, map[string]interface{}{
 			"message": "Cart is empty",
 			"data": map[string]interface{}{
 				"cart":   nil,
 				"items":  []models.CartItem{},
+				"combos": []models.CartCombos{},
 				"length": 0,
 				"total":  0,
 			},
@@ -271,7 +508,15 @@ func (c *CartController) GetCartByUserID(ctx http.Context) http.Response {
 	}
 
 	var cartItems []models.CartItem
-	if err := facades.Orm().Query().Where("cart_id = ?", cart.ID).With("Product").Find(&cartItems); err != nil {
+	if err := facades.Orm().Query().Where("cart_id = ? AND cart_combo_id IS NULL", cart.ID).With("Product").With("Variant").With("Modifiers.Option").Find(&cartItems); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	cartCombos, err := services.LoadCartCombos(cart.ID)
+	if err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
 			"error":   err.Error(),
@@ -281,7 +526,10 @@ func (c *CartController) GetCartByUserID(ctx http.Context) http.Response {
 	// Calculate total
 	var total float64
 	for _, item := range cartItems {
-		total += item.Product.Price * float64(item.Quantity)
+		total += services.CartItemUnitPrice(item) * float64(item.Quantity)
+	}
+	for _, cartCombo := range cartCombos {
+		total += cartCombo.UnitPrice * float64(cartCombo.Quantity)
 	}
 
 	return ctx.Response().Json(200, map[string]interface{}{
@@ -289,7 +537,8 @@ func (c *CartController) GetCartByUserID(ctx http.Context) http.Response {
 		"data": map[string]interface{}{
 			"cart":   cart,
 			"items":  cartItems,
-			"length": len(cartItems),
+			"combos": cartCombos,
+			"length": len(cartItems) + len(cartCombos),
 			"total":  total,
 		},
 	})
@@ -322,9 +571,46 @@ func (c *CartController) UpdateCartItem(ctx http.Context) http.Response {
 		})
 	}
 
+	owner, err := cartOwnerFromRequest(ctx)
+	if err != nil {
+		return cartOwnerFailed(ctx, err)
+	}
+	cart, err := owner.activeCart()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
 	cartItemID := req.CartItemID
 	quantity := req.Quantity
 
+	var currentItem models.CartItem
+	if err := facades.Orm().Query().Where("id = ? AND cart_id = ?", cartItemID, cart.ID).With("Variant").First(&currentItem); err != nil || currentItem.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Cart item not found",
+		})
+	}
+	if currentItem.CartComboID != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Món này thuộc combo, vui lòng cập nhật số lượng combo",
+		})
+	}
+
+	canMake, err := services.CanMakeProduct(currentItem.ProductID, float64(quantity)*services.RecipeFactor(currentItem.Variant))
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if !canMake {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Sản phẩm đã hết nguyên liệu",
+		})
+	}
+
 	tx, err := facades.Orm().Query().Begin()
 	if err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
@@ -337,7 +623,7 @@ func (c *CartController) UpdateCartItem(ctx http.Context) http.Response {
 		Quantity: quantity,
 	}
 
-	if _, err := tx.Model(&models.CartItem{}).Where("id = ?", cartItemID).Update(cartItemUpdate); err != nil {
+	if _, err := tx.Model(&models.CartItem{}).Where("id = ? AND cart_id = ?", cartItemID, cart.ID).Update(cartItemUpdate); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
@@ -368,6 +654,18 @@ func (c *CartController) RemoveItemFromCart(ctx http.Context) http.Response {
 		})
 	}
 
+	owner, err := cartOwnerFromRequest(ctx)
+	if err != nil {
+		return cartOwnerFailed(ctx, err)
+	}
+	cart, err := owner.activeCart()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
 	tx, err := facades.Orm().Query().Begin()
 	if err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
@@ -376,7 +674,8 @@ func (c *CartController) RemoveItemFromCart(ctx http.Context) http.Response {
 		})
 	}
 
-	if _, err := tx.Model(&models.CartItem{}).Where("id = ?", cartItemID).Delete(); err != nil {
+	// Combo components are removed together with their combo
+	if err := services.DeleteCartItems(tx, "id = ? AND cart_id = ? AND cart_combo_id IS NULL", cartItemID, cart.ID); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
@@ -396,3 +695,431 @@ func (c *CartController) RemoveItemFromCart(ctx http.Context) http.Response {
 		"message": "Cart item removed successfully",
 	})
 }
+
+// AddComboToCart - Thêm combo vào giỏ hàng, mỗi món trong combo là một dòng
+// cart item gắn với combo để bếp và kho thấy đúng món, còn giá tính theo combo.
+// Body: {"combo_id":1,"quantity":1,"choices":[{"slot_id":2,"option_id":5}]}
+func (c *CartController) AddComboToCart(ctx http.Context) http.Response {
+	type ChoiceRequest struct {
+		SlotID   int64 `json:"slot_id"`
+		OptionID int64 `json:"option_id"`
+	}
+	type AddComboRequest struct {
+		ComboID  int64           `json:"combo_id"`
+		Quantity int             `json:"quantity"`
+		Choices  []ChoiceRequest `json:"choices"`
+	}
+
+	var req AddComboRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	// Validate
+	if req.ComboID <= 0 {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "combo_id is required and must be greater than 0",
+		})
+	}
+	if req.Quantity <= 0 {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "quantity is required and must be greater than 0",
+		})
+	}
+
+	owner, err := cartOwnerFromRequest(ctx)
+	if err != nil {
+		return cartOwnerFailed(ctx, err)
+	}
+
+	combo, err := services.ActiveCombo(req.ComboID)
+	if err != nil {
+		if errors.Is(err, services.ErrComboNotFound) {
+			return ctx.Response().Json(404, map[string]interface{}{
+				"message": "Combo không tồn tại hoặc đã ngừng bán",
+			})
+		}
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	choices := map[int64]int64{}
+	for _, choice := range req.Choices {
+		choices[choice.SlotID] = choice.OptionID
+	}
+	options, err := services.ResolveComboChoices(combo, choices)
+	if err != nil {
+		if errors.Is(err, services.ErrComboChoiceInvalid) {
+			return ctx.Response().Json(422, map[string]interface{}{
+				"message": "Lựa chọn món trong combo không hợp lệ",
+				"error":   err.Error(),
+			})
+		}
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	productIDs := make([]int64, 0, len(options))
+	for _, option := range options {
+		productIDs = append(productIDs, option.ProductID)
+	}
+	offSchedule, err := services.OffScheduleProductIDs(productIDs, services.RestaurantNow())
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	hidden, err := services.HiddenCategoryProductIDs(productIDs)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	for _, option := range options {
+		if offSchedule[option.ProductID] {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message":    "Sản phẩm trong combo không phục vụ vào khung giờ này",
+				"product_id": option.ProductID,
+			})
+		}
+		if hidden[option.ProductID] {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message":    "Sản phẩm trong combo không còn bán",
+				"product_id": option.ProductID,
+			})
+		}
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	// Find or create active cart for user, or a guest cart for visitors
+	cart, err := owner.activeCart()
+	if err != nil || cart.ID == 0 {
+		if cart, err = owner.newCart(tx); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	// The same combo with the same choices shares one line
+	var existing models.CartCombos
+	var candidates []models.CartCombos
+	facades.Orm().Query().Where("cart_id = ? AND combo_id = ?", cart.ID, combo.ID).With("Items").Find(&candidates)
+	for _, candidate := range candidates {
+		if services.SameComboChoices(candidate, options) {
+			existing = candidate
+			break
+		}
+	}
+	quantity := existing.Quantity + req.Quantity
+
+	// Reject the combo if the kitchen cannot make every component
+	for _, option := range options {
+		canMake, err := services.CanMakeProduct(option.ProductID, float64(option.Slot.Quantity*quantity)*services.RecipeFactor(option.Variant))
+		if err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		if !canMake {
+			tx.Rollback()
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message":    "Sản phẩm trong combo đã hết nguyên liệu",
+				"product_id": option.ProductID,
+			})
+		}
+	}
+
+	if existing.ID > 0 {
+		if err := services.SetCartComboQuantity(tx, existing, quantity); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		if err := tx.Commit(); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		existing.Quantity = quantity
+		body, err := withCartToken(map[string]interface{}{
+			"message": "Cart combo quantity updated",
+			"data":    existing,
+		}, cart)
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		return ctx.Response().Json(200, body)
+	}
+
+	cartCombo := models.CartCombos{
+		CartID:   cart.ID,
+		ComboID:  combo.ID,
+		Quantity: req.Quantity,
+	}
+	if err := tx.Create(&cartCombo); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	for _, option := range options {
+		optionID := option.ID
+		cartItem := models.CartItem{
+			CartID:            cart.ID,
+			ProductID:         option.ProductID,
+			VariantID:         option.VariantID,
+			Quantity:          option.Slot.Quantity * req.Quantity,
+			CartComboID:       &cartCombo.ID,
+			ComboSlotOptionID: &optionID,
+		}
+		if err := tx.Create(&cartItem); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		cartCombo.Items = append(cartCombo.Items, cartItem)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	body, err := withCartToken(map[string]interface{}{
+		"message": "Combo added to cart successfully",
+		"data":    cartCombo,
+	}, cart)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, body)
+}
+
+// UpdateCartCombo - Cập nhật số lượng combo trong giỏ hàng
+func (c *CartController) UpdateCartCombo(ctx http.Context) http.Response {
+	type UpdateComboRequest struct {
+		CartComboID int64 `json:"cart_combo_id"`
+		Quantity    int   `json:"quantity"`
+	}
+
+	var req UpdateComboRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	// Validate
+	if req.CartComboID <= 0 {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "cart_combo_id is required and must be greater than 0",
+		})
+	}
+	if req.Quantity <= 0 {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "quantity is required and must be greater than 0",
+		})
+	}
+
+	owner, err := cartOwnerFromRequest(ctx)
+	if err != nil {
+		return cartOwnerFailed(ctx, err)
+	}
+	cart, err := owner.activeCart()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	var cartCombo models.CartCombos
+	if err := facades.Orm().Query().
+		Where("id = ? AND cart_id = ?", req.CartComboID, cart.ID).
+		With("Items.Variant").
+		First(&cartCombo); err != nil || cartCombo.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Cart combo not found",
+		})
+	}
+
+	for _, item := range cartCombo.Items {
+		perCombo := item.Quantity / cartCombo.Quantity
+		canMake, err := services.CanMakeProduct(item.ProductID, float64(perCombo*req.Quantity)*services.RecipeFactor(item.Variant))
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		if !canMake {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message":    "Sản phẩm trong combo đã hết nguyên liệu",
+				"product_id": item.ProductID,
+			})
+		}
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := services.SetCartComboQuantity(tx, cartCombo, req.Quantity); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Cart combo updated successfully",
+	})
+}
+
+// RemoveComboFromCart - Xoá combo và các món của combo khỏi giỏ hàng
+func (c *CartController) RemoveComboFromCart(ctx http.Context) http.Response {
+	cartComboID, err := strconv.ParseInt(ctx.Request().Route("cart_combo_id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "Invalid cart_combo_id",
+		})
+	}
+
+	owner, err := cartOwnerFromRequest(ctx)
+	if err != nil {
+		return cartOwnerFailed(ctx, err)
+	}
+	cart, err := owner.activeCart()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := services.DeleteCartCombos(tx, "id = ? AND cart_id = ?", cartComboID, cart.ID); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Cart combo removed successfully",
+	})
+}
+
+// GetRecommendations - Gợi ý món thường được mua kèm với các món trong giỏ hàng
+func (c *CartController) GetRecommendations(ctx http.Context) http.Response {
+	owner, err := cartOwnerFromRequest(ctx)
+	if err != nil {
+		return cartOwnerFailed(ctx, err)
+	}
+	cart, err := owner.activeCart()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	limit, err := strconv.Atoi(ctx.Request().Query("limit", "6"))
+	if err != nil || limit < 1 || limit > 20 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "limit phải từ 1 đến 20",
+		})
+	}
+
+	var productIDs []int64
+	if err := facades.Orm().Query().Model(&models.CartItem{}).
+		Where("cart_id = ?", cart.ID).
+		Distinct("product_id").Pluck("product_id", &productIDs); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	locale := utils.GetLocale(ctx)
+	recommendations, err := services.RecommendProducts(productIDs, nil, limit, locale)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Header("Content-Language", locale).Json(200, map[string]interface{}{
+		"message": "Recommendations fetched successfully",
+		"data":    recommendations,
+	})
+}
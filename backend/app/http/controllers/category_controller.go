@@ -1,8 +1,12 @@
 package controllers
 
 import (
+	"errors"
+	"goravel/app/http/utils"
 	"goravel/app/models"
+	"goravel/app/services"
 	"strconv"
+	"strings"
 
 	"github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/facades"
@@ -11,6 +15,63 @@ import (
 type CategoryController struct {
 }
 
+// categoryAttributes reads the optional parent_id, sort_order, status and icon
+// inputs that were sent. An empty or null parent_id moves the category to the
+// top level. The response is non-nil when an input is invalid.
+func categoryAttributes(ctx http.Context, categoryID int64) (map[string]interface{}, http.Response) {
+	inputs := ctx.Request().All()
+	attributes := map[string]interface{}{}
+
+	if _, ok := inputs["parent_id"]; ok {
+		parentStr := ctx.Request().Input("parent_id")
+		if parentStr == "" || parentStr == "null" {
+			attributes["parent_id"] = nil
+		} else {
+			parentID, err := strconv.ParseInt(parentStr, 10, 64)
+			if err != nil {
+				return nil, ctx.Response().Json(422, http.Json{
+					"message": "Invalid parent_id",
+				})
+			}
+			if err := services.ValidateCategoryParent(categoryID, parentID); err != nil {
+				if errors.Is(err, services.ErrCategoryParentNotFound) || errors.Is(err, services.ErrCategoryCycle) {
+					return nil, ctx.Response().Json(422, http.Json{
+						"message": err.Error(),
+					})
+				}
+				return nil, ctx.Response().Json(500, map[string]interface{}{
+					"message": "Internal server error",
+					"error":   err.Error(),
+				})
+			}
+			attributes["parent_id"] = parentID
+		}
+	}
+	if _, ok := inputs["sort_order"]; ok {
+		sortOrder, err := strconv.Atoi(ctx.Request().Input("sort_order"))
+		if err != nil {
+			return nil, ctx.Response().Json(422, http.Json{
+				"message": "Invalid sort_order",
+			})
+		}
+		attributes["sort_order"] = sortOrder
+	}
+	if _, ok := inputs["status"]; ok {
+		status, err := strconv.ParseBool(ctx.Request().Input("status"))
+		if err != nil {
+			return nil, ctx.Response().Json(422, http.Json{
+				"message": "Invalid status",
+			})
+		}
+		attributes["status"] = status
+	}
+	if _, ok := inputs["icon"]; ok {
+		attributes["icon"] = strings.TrimSpace(ctx.Request().Input("icon"))
+	}
+
+	return attributes, nil
+}
+
 func (category *CategoryController) Create(ctx http.Context) http.Response {
 	var err error
 	validator, err := ctx.Request().Validate(map[string]string{
@@ -29,6 +90,11 @@ func (category *CategoryController) Create(ctx http.Context) http.Response {
 		})
 	}
 
+	attributes, response := categoryAttributes(ctx, 0)
+	if response != nil {
+		return response
+	}
+
 	tx, err := facades.Orm().Query().Begin()
 	if err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
@@ -38,7 +104,35 @@ func (category *CategoryController) Create(ctx http.Context) http.Response {
 	}
 
 	categoryModel := models.Category{
-		Name: ctx.Request().Input("name"),
+		Name:   ctx.Request().Input("name"),
+		Status: true,
+	}
+	if parentID, ok := attributes["parent_id"].(int64); ok {
+		categoryModel.ParentID = &parentID
+	}
+	if status, ok := attributes["status"].(bool); ok {
+		categoryModel.Status = status
+	}
+	if icon, ok := attributes["icon"].(string); ok {
+		categoryModel.Icon = icon
+	}
+	// New categories go to the end of their level unless a position is given
+	if sortOrder, ok := attributes["sort_order"].(int); ok {
+		categoryModel.SortOrder = sortOrder
+	} else {
+		siblings := tx.Model(&models.Category{}).Where("parent_id IS NULL")
+		if categoryModel.ParentID != nil {
+			siblings = tx.Model(&models.Category{}).Where("parent_id = ?", *categoryModel.ParentID)
+		}
+		count, err := siblings.Count()
+		if err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		categoryModel.SortOrder = int(count)
 	}
 
 	if err = tx.Create(&categoryModel); err != nil {
@@ -81,6 +175,18 @@ func (category *CategoryController) Update(ctx http.Context) http.Response {
 		})
 	}
 
+	id, err := strconv.ParseInt(ctx.Request().Input("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+	attributes, response := categoryAttributes(ctx, id)
+	if response != nil {
+		return response
+	}
+	attributes["name"] = ctx.Request().Input("name")
+
 	tx, err := facades.Orm().Query().Begin()
 	if err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
@@ -89,11 +195,7 @@ func (category *CategoryController) Update(ctx http.Context) http.Response {
 		})
 	}
 
-	categoryModel := models.Category{
-		Name: ctx.Request().Input("name"),
-	}
-
-	if _, err = tx.Model(&models.Category{}).Where("id", ctx.Request().Input("id")).Update(&categoryModel); err != nil {
+	if _, err = tx.Model(&models.Category{}).Where("id", id).Update(attributes); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Failed to update category",
@@ -108,6 +210,14 @@ func (category *CategoryController) Update(ctx http.Context) http.Response {
 		})
 	}
 
+	categoryModel := models.Category{}
+	if err = facades.Orm().Query().Where("id", id).First(&categoryModel); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
 	return ctx.Response().Json(200, map[string]interface{}{
 		"message": "Category updated successfully",
 		"data":    categoryModel,
@@ -147,6 +257,31 @@ func (category *CategoryController) Delete(ctx http.Context) http.Response {
 		})
 	}
 
+	// Child categories move up to the parent of the deleted category
+	var deleted models.Category
+	if err = tx.Where("id", id).First(&deleted); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if _, err = tx.Model(&models.Category{}).Where("parent_id", id).Update("parent_id", deleted.ParentID); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete category",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err = tx.Model(&models.CategoryTranslations{}).Where("category_id", id).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete category",
+			"error":   err.Error(),
+		})
+	}
+
 	if _, err = tx.Model(&models.Category{}).Where("id", id).Delete(); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
@@ -167,17 +302,46 @@ func (category *CategoryController) Delete(ctx http.Context) http.Response {
 	})
 }
 
+// GetAll lists categories in display order. Inactive categories are left out
+// unless include_inactive=true. With tree=true the categories are nested
+// under their parents and carry product counts.
 func (category *CategoryController) GetAll(ctx http.Context) http.Response {
 	var err error
+	query := facades.Orm().Query().Model(&models.Category{})
+	if ctx.Request().Query("include_inactive") != "true" {
+		query = query.Where("status = ?", true)
+	}
 	categories := []models.Category{}
-	if err = facades.Orm().Query().Model(&models.Category{}).Find(&categories); err != nil {
+	if err = query.OrderBy("sort_order").OrderBy("id").Find(&categories); err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
 			"error":   err.Error(),
 		})
 	}
 
-	return ctx.Response().Json(200, map[string]interface{}{
+	locale := utils.GetLocale(ctx)
+	if err = services.TranslateCategories(categories, locale); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if ctx.Request().Query("tree") == "true" {
+		counts, err := services.ProductCountsByCategory()
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		return ctx.Response().Header("Content-Language", locale).Json(200, map[string]interface{}{
+			"message": "Categories fetched successfully",
+			"data":    services.CategoryTree(categories, counts),
+		})
+	}
+
+	return ctx.Response().Header("Content-Language", locale).Json(200, map[string]interface{}{
 		"message": "Categories fetched successfully",
 		"data":    categories,
 	})
@@ -201,8 +365,123 @@ func (category *CategoryController) GetById(ctx http.Context) http.Response {
 		})
 	}
 
-	return ctx.Response().Json(200, map[string]interface{}{
+	locale := utils.GetLocale(ctx)
+	translated := []models.Category{categoryModel}
+	if err = services.TranslateCategories(translated, locale); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Header("Content-Language", locale).Json(200, map[string]interface{}{
 		"message": "Category fetched successfully",
-		"data":    categoryModel,
+		"data":    translated[0],
+	})
+}
+
+// Reorder sets the position, and optionally the parent, of several
+// categories at once, e.g. after a drag and drop in the admin menu editor.
+// Body: {"categories":[{"id":3,"parent_id":null,"sort_order":0},{"id":1,"parent_id":3,"sort_order":0}]}
+func (category *CategoryController) Reorder(ctx http.Context) http.Response {
+	type reorderItem struct {
+		ID        int64  `json:"id"`
+		ParentID  *int64 `json:"parent_id"`
+		SortOrder int    `json:"sort_order"`
+	}
+	type reorderRequest struct {
+		Categories []reorderItem `json:"categories"`
+	}
+
+	var req reorderRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if len(req.Categories) == 0 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "categories is required",
+		})
+	}
+
+	var categories []models.Category
+	if err := facades.Orm().Query().Find(&categories); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	parents := map[int64]*int64{}
+	for _, existing := range categories {
+		parents[existing.ID] = existing.ParentID
+	}
+
+	// Apply the moves to the current hierarchy first, then check it has no loops
+	seen := map[int64]bool{}
+	for _, item := range req.Categories {
+		if _, ok := parents[item.ID]; !ok {
+			return ctx.Response().Json(404, map[string]interface{}{
+				"message": "Category not found",
+				"id":      item.ID,
+			})
+		}
+		if seen[item.ID] {
+			return ctx.Response().Json(422, http.Json{
+				"message": "Each category can only be given once",
+			})
+		}
+		seen[item.ID] = true
+		if item.ParentID != nil {
+			if _, ok := parents[*item.ParentID]; !ok {
+				return ctx.Response().Json(422, http.Json{
+					"message": services.ErrCategoryParentNotFound.Error(),
+				})
+			}
+		}
+		parents[item.ID] = item.ParentID
+	}
+	for _, item := range req.Categories {
+		steps := 0
+		for parent := parents[item.ID]; parent != nil; parent = parents[*parent] {
+			if *parent == item.ID || steps > len(parents) {
+				return ctx.Response().Json(422, http.Json{
+					"message": services.ErrCategoryCycle.Error(),
+				})
+			}
+			steps++
+		}
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	for _, item := range req.Categories {
+		if _, err := tx.Model(&models.Category{}).Where("id", item.ID).Update(map[string]interface{}{
+			"parent_id":  item.ParentID,
+			"sort_order": item.SortOrder,
+		}); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to reorder categories",
+				"error":   err.Error(),
+			})
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Categories reordered successfully",
 	})
 }
@@ -0,0 +1,384 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type ComboController struct {
+}
+
+type comboSlotOptionRequest struct {
+	ProductID  int64   `json:"product_id"`
+	VariantID  *int64  `json:"variant_id"`
+	ExtraPrice float64 `json:"extra_price"`
+}
+
+type comboSlotRequest struct {
+	Name     string                   `json:"name"`
+	Quantity int                      `json:"quantity"`
+	Options  []comboSlotOptionRequest `json:"options"`
+}
+
+type comboRequest struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Thumbnail   string             `json:"thumbnail"`
+	Price       *float64           `json:"price"`
+	Status      *bool              `json:"status"`
+	Slots       []comboSlotRequest `json:"slots"`
+}
+
+// validateComboRequest returns a message for the client when the request is
+// invalid. Products sold in sizes must name the variant that goes in the combo.
+func validateComboRequest(req *comboRequest) (string, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "name is required", nil
+	}
+	if req.Price == nil || *req.Price < 0 {
+		return "price is required and cannot be negative", nil
+	}
+	if len(req.Slots) == 0 {
+		return "a combo needs at least one slot", nil
+	}
+
+	for slotIndex := range req.Slots {
+		slot := &req.Slots[slotIndex]
+		slot.Name = strings.TrimSpace(slot.Name)
+		if slot.Name == "" {
+			return "slot name is required", nil
+		}
+		if slot.Quantity == 0 {
+			slot.Quantity = 1
+		}
+		if slot.Quantity < 0 {
+			return "slot quantity cannot be negative", nil
+		}
+		if len(slot.Options) == 0 {
+			return "each slot needs at least one product", nil
+		}
+
+		for _, option := range slot.Options {
+			if option.ExtraPrice < 0 {
+				return "extra_price cannot be negative", nil
+			}
+			var productModel models.Product
+			if err := facades.Orm().Query().Where("id = ?", option.ProductID).First(&productModel); err != nil {
+				return "", err
+			}
+			if productModel.ID == 0 {
+				return "product " + strconv.FormatInt(option.ProductID, 10) + " not found", nil
+			}
+			if _, err := services.ResolveVariant(option.ProductID, option.VariantID); err != nil {
+				if errors.Is(err, services.ErrVariantRequired) || errors.Is(err, services.ErrVariantNotFound) {
+					return productModel.Name + ": " + err.Error(), nil
+				}
+				return "", err
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// saveComboSlots creates the slots and options of a combo.
+func saveComboSlots(tx orm.Query, combo *models.Combos, slots []comboSlotRequest) error {
+	combo.Slots = []models.ComboSlots{}
+	for slotIndex, slotReq := range slots {
+		slot := models.ComboSlots{
+			ComboID:   combo.ID,
+			Name:      slotReq.Name,
+			Quantity:  slotReq.Quantity,
+			SortOrder: slotIndex,
+		}
+		if err := tx.Create(&slot); err != nil {
+			return err
+		}
+		for _, optionReq := range slotReq.Options {
+			option := models.ComboSlotOptions{
+				SlotID:     slot.ID,
+				ProductID:  optionReq.ProductID,
+				ExtraPrice: optionReq.ExtraPrice,
+			}
+			if optionReq.VariantID != nil && *optionReq.VariantID != 0 {
+				option.VariantID = optionReq.VariantID
+			}
+			if err := tx.Create(&option); err != nil {
+				return err
+			}
+			slot.Options = append(slot.Options, option)
+		}
+		combo.Slots = append(combo.Slots, slot)
+	}
+
+	return nil
+}
+
+func comboSlotsInOrder(query orm.Query) orm.Query {
+	return query.OrderBy("sort_order").OrderBy("id")
+}
+
+// GetAll - Lấy danh sách combo đang bán. Admin có thể thêm ?all=true để xem cả combo đã ngừng bán
+func (c *ComboController) GetAll(ctx http.Context) http.Response {
+	query := facades.Orm().Query().Model(&models.Combos{})
+	if ctx.Request().Query("all") != "true" {
+		query = query.Where("status = ?", true)
+	}
+
+	combos := []models.Combos{}
+	if err := query.With("Slots", comboSlotsInOrder).With("Slots.Options.Product").With("Slots.Options.Variant").OrderBy("name").Find(&combos); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Combos fetched successfully",
+		"data":    combos,
+	})
+}
+
+func (c *ComboController) GetById(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	combo, err := services.ActiveCombo(id)
+	if err != nil {
+		if errors.Is(err, services.ErrComboNotFound) {
+			return ctx.Response().Json(404, map[string]interface{}{
+				"message": "Combo not found",
+			})
+		}
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Combo fetched successfully",
+		"data":    combo,
+	})
+}
+
+// Create defines a combo. A slot with one product is a fixed item, a slot
+// with several is a choice such as "any drink".
+// Body: {"name":"Combo trưa","price":89000,"slots":[{"name":"Món chính","options":[{"product_id":1}]},{"name":"Đồ uống","options":[{"product_id":7},{"product_id":8,"extra_price":5000}]}]}
+func (c *ComboController) Create(ctx http.Context) http.Response {
+	var req comboRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	message, err := validateComboRequest(&req)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	combo := models.Combos{
+		Name:        req.Name,
+		Description: req.Description,
+		Thumbnail:   req.Thumbnail,
+		Price:       *req.Price,
+		Status:      req.Status == nil || *req.Status,
+	}
+	if err := tx.Create(&combo); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if err := saveComboSlots(tx, &combo, req.Slots); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Combo created successfully",
+		"data":    combo,
+	})
+}
+
+// Update replaces the combo and its slots. Carts holding the combo are
+// emptied of it since their choices may no longer exist.
+func (c *ComboController) Update(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req comboRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	var combo models.Combos
+	if err := facades.Orm().Query().Where("id = ?", id).First(&combo); err != nil || combo.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Combo not found",
+		})
+	}
+
+	message, err := validateComboRequest(&req)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	combo.Name = req.Name
+	combo.Description = req.Description
+	combo.Thumbnail = req.Thumbnail
+	combo.Price = *req.Price
+	combo.Status = req.Status == nil || *req.Status
+	if err := tx.Save(&combo); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update combo",
+			"error":   err.Error(),
+		})
+	}
+	if err := services.DeleteCartCombos(tx, "combo_id = ?", combo.ID); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update combo",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := tx.Model(&models.ComboSlots{}).Where("combo_id = ?", combo.ID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update combo",
+			"error":   err.Error(),
+		})
+	}
+	if err := saveComboSlots(tx, &combo, req.Slots); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update combo",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Combo updated successfully",
+		"data":    combo,
+	})
+}
+
+// Delete removes a combo and takes it out of carts. Orders keep the combo
+// name and price they were sold with.
+func (c *ComboController) Delete(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := services.DeleteCartCombos(tx, "combo_id = ?", id); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete combo",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := tx.Model(&models.Combos{}).Where("id = ?", id).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete combo",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Combo deleted successfully",
+	})
+}
@@ -0,0 +1,785 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/http/utils"
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type IngredientController struct {
+}
+
+func (i *IngredientController) Create(ctx http.Context) http.Response {
+	validator, err := ctx.Request().Validate(map[string]string{
+		"name":       "required|string",
+		"unit":       "required|string",
+		"quantity":   "numeric",
+		"threshold":  "required|numeric",
+		"expires_at": "date",
+	})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if validator.Fails() {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "Validation failed",
+			"errors":  validator.Errors().All(),
+		})
+	}
+
+	var quantity float64
+	if quantityStr := ctx.Request().Input("quantity"); quantityStr != "" {
+		quantity, err = strconv.ParseFloat(quantityStr, 64)
+		if err != nil || quantity < 0 {
+			return ctx.Response().Json(422, http.Json{
+				"message": "Invalid quantity",
+			})
+		}
+	}
+
+	threshold, err := strconv.ParseFloat(ctx.Request().Input("threshold"), 64)
+	if err != nil || threshold < 0 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid threshold",
+		})
+	}
+
+	unit, err := services.NormalizeUnit(ctx.Request().Input("unit"))
+	if err != nil {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message":     "Unknown unit",
+			"valid_units": services.Units(),
+		})
+	}
+
+	expiresAt, ok := parseExpiryDate(ctx)
+	if !ok {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid expires_at, please use YYYY-MM-DD",
+		})
+	}
+
+	userID, _ := utils.GetUserIDFromToken(ctx)
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	// Stock always starts at zero so the opening quantity shows up in the movement history
+	ingredient := models.Ingredients{
+		Name:      ctx.Request().Input("name"),
+		Unit:      unit,
+		Threshold: threshold,
+	}
+	if err := tx.Create(&ingredient); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if quantity > 0 {
+		batch := models.IngredientBatches{IngredientID: ingredient.ID, Quantity: quantity, ExpiresAt: expiresAt}
+		if _, err := services.ReceiveStock(tx, batch, services.StockMovementCorrection, "Initial stock", userID); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		ingredient.Quantity = quantity
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Ingredient created successfully",
+		"data":    ingredient,
+	})
+}
+
+func (i *IngredientController) GetAll(ctx http.Context) http.Response {
+	ingredients := []models.Ingredients{}
+	if err := facades.Orm().Query().Model(&models.Ingredients{}).Order("name asc").Find(&ingredients); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Ingredients fetched successfully",
+		"data":    ingredients,
+	})
+}
+
+func (i *IngredientController) GetById(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var ingredient models.Ingredients
+	if err := facades.Orm().Query().Where("id = ?", id).First(&ingredient); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if ingredient.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Ingredient not found",
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Ingredient fetched successfully",
+		"data":    ingredient,
+	})
+}
+
+// Update changes the ingredient details. Quantity is intentionally not editable
+// here, stock changes must go through AdjustStock so they are recorded.
+// Changing the unit is only allowed within the same dimension (kg to g, not
+// kg to l) and rescales the stored stock, batches and recipes.
+func (i *IngredientController) Update(ctx http.Context) http.Response {
+	validator, err := ctx.Request().Validate(map[string]string{
+		"name":      "required|string",
+		"unit":      "required|string",
+		"threshold": "required|numeric",
+	})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if validator.Fails() {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "Validation failed",
+			"errors":  validator.Errors().All(),
+		})
+	}
+
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	unit, err := services.NormalizeUnit(ctx.Request().Input("unit"))
+	if err != nil {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message":     "Unknown unit",
+			"valid_units": services.Units(),
+		})
+	}
+
+	var ingredient models.Ingredients
+	if err := facades.Orm().Query().Where("id = ?", id).First(&ingredient); err != nil || ingredient.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Ingredient not found",
+		})
+	}
+
+	// Threshold is given in the new unit
+	threshold, err := strconv.ParseFloat(ctx.Request().Input("threshold"), 64)
+	if err != nil || threshold < 0 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid threshold",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if unit != ingredient.Unit {
+		if err := tx.Model(&models.Ingredients{}).LockForUpdate().Where("id = ?", id).First(&ingredient); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		if err := services.ChangeIngredientUnit(tx, ingredient, unit); err != nil {
+			tx.Rollback()
+			if errors.Is(err, services.ErrIncompatibleUnits) {
+				return ctx.Response().Json(422, map[string]interface{}{
+					"message": "Unit can only be changed within the same dimension",
+					"error":   err.Error(),
+				})
+			}
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to update ingredient",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	if _, err := tx.Model(&models.Ingredients{}).Where("id = ?", id).Update(map[string]interface{}{
+		"name":      ctx.Request().Input("name"),
+		"threshold": threshold,
+	}); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update ingredient",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := facades.Orm().Query().Where("id = ?", id).First(&ingredient); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Ingredient updated successfully",
+		"data":    ingredient,
+	})
+}
+
+// UpdateNutrition sets the nutrition values of an ingredient, given for an
+// amount in any unit of its dimension (e.g. per 100 g), and refreshes the
+// nutrition of every product using it.
+// Body: {"per":100,"unit":"g","kcal":250,"protein":26,"fat":15,"carbs":0,"sodium":70}
+func (i *IngredientController) UpdateNutrition(ctx http.Context) http.Response {
+	type NutritionRequest struct {
+		Per     float64 `json:"per"`
+		Unit    string  `json:"unit"`
+		Kcal    float64 `json:"kcal"`
+		Protein float64 `json:"protein"`
+		Fat     float64 `json:"fat"`
+		Carbs   float64 `json:"carbs"`
+		Sodium  float64 `json:"sodium"`
+	}
+
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req NutritionRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if req.Per == 0 {
+		req.Per = 1
+	}
+	if req.Per < 0 || req.Kcal < 0 || req.Protein < 0 || req.Fat < 0 || req.Carbs < 0 || req.Sodium < 0 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Nutrition values must not be negative",
+		})
+	}
+
+	var ingredient models.Ingredients
+	if err := facades.Orm().Query().Where("id = ?", id).First(&ingredient); err != nil || ingredient.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Ingredient not found",
+		})
+	}
+
+	// Values are stored per one unit of the ingredient
+	amount, err := services.ConvertQuantity(req.Per, req.Unit, ingredient.Unit)
+	if err != nil {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": err.Error(),
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.Ingredients{}).Where("id = ?", id).Update(map[string]interface{}{
+		"has_nutrition": true,
+		"kcal":          req.Kcal / amount,
+		"protein":       req.Protein / amount,
+		"fat":           req.Fat / amount,
+		"carbs":         req.Carbs / amount,
+		"sodium":        req.Sodium / amount,
+	}); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update nutrition",
+			"error":   err.Error(),
+		})
+	}
+	if err := services.RecalculateIngredientNutrition(tx, id); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update nutrition",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := facades.Orm().Query().Where("id = ?", id).First(&ingredient); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Ingredient nutrition updated successfully",
+		"data":    ingredient,
+	})
+}
+
+func (i *IngredientController) Delete(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	// Ingredients still used by a recipe cannot be removed
+	usedCount, err := facades.Orm().Query().Model(&models.ProductIngredient{}).Where("ingredient_id = ?", id).Count()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if usedCount > 0 {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Ingredient is used by product recipes",
+		})
+	}
+
+	// Stock movements, purchase orders and stocktakes are the audit trail and
+	// must keep pointing at the ingredient
+	for _, history := range []any{&models.StockMovements{}, &models.PurchaseOrderLines{}, &models.StocktakeLines{}} {
+		historyCount, err := facades.Orm().Query().Model(history).Where("ingredient_id = ?", id).Count()
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		if historyCount > 0 {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message": "Ingredient has stock history and cannot be deleted",
+			})
+		}
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.IngredientTags{}).Where("ingredient_id = ?", id).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete ingredient",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.IngredientBatches{}).Where("ingredient_id = ?", id).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete ingredient",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.Ingredients{}).Where("id = ?", id).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete ingredient",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Ingredient deleted successfully",
+	})
+}
+
+// AdjustStock records a stock change for an ingredient.
+// For "purchase" and "waste" the quantity must be positive and is added or
+// removed accordingly; for "correction" the quantity is a signed delta.
+// Added stock becomes a new batch, optionally with an expires_at date.
+// The quantity may be given in another unit of the same dimension.
+func (i *IngredientController) AdjustStock(ctx http.Context) http.Response {
+	validator, err := ctx.Request().Validate(map[string]string{
+		"type":       "required|string",
+		"quantity":   "required|numeric",
+		"unit":       "string",
+		"reason":     "required|string",
+		"expires_at": "date",
+	})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if validator.Fails() {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "Validation failed",
+			"errors":  validator.Errors().All(),
+		})
+	}
+
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	quantity, err := strconv.ParseFloat(ctx.Request().Input("quantity"), 64)
+	if err != nil || quantity == 0 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid quantity",
+		})
+	}
+
+	movementType := ctx.Request().Input("type")
+	change := quantity
+	switch movementType {
+	case services.StockMovementPurchase, services.StockMovementCorrection:
+	case services.StockMovementWaste:
+		change = -quantity
+	default:
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message":     "Invalid adjustment type",
+			"valid_types": []string{services.StockMovementPurchase, services.StockMovementWaste, services.StockMovementCorrection},
+		})
+	}
+	if movementType != services.StockMovementCorrection && quantity < 0 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Quantity must be greater than 0",
+		})
+	}
+
+	expiresAt, ok := parseExpiryDate(ctx)
+	if !ok {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid expires_at, please use YYYY-MM-DD",
+		})
+	}
+
+	userID, err := utils.GetUserIDFromToken(ctx)
+	if err != nil || userID == 0 {
+		return ctx.Response().Json(401, map[string]interface{}{
+			"message": "Unauthorized - user_id not found",
+		})
+	}
+
+	var ingredient models.Ingredients
+	if err := facades.Orm().Query().Where("id = ?", id).First(&ingredient); err != nil || ingredient.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Ingredient not found",
+		})
+	}
+
+	change, err = services.ConvertQuantity(change, ctx.Request().Input("unit"), ingredient.Unit)
+	if err != nil {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": err.Error(),
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	var movements []models.StockMovements
+	if change > 0 {
+		batch := models.IngredientBatches{IngredientID: id, Quantity: change, UnitCost: ingredient.UnitCost, ExpiresAt: expiresAt}
+		movements, err = services.ReceiveStock(tx, batch, movementType, ctx.Request().Input("reason"), userID)
+	} else {
+		movements, err = services.AdjustStock(tx, id, change, movementType, ctx.Request().Input("reason"), userID)
+	}
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, services.ErrIngredientNotFound) {
+			return ctx.Response().Json(404, map[string]interface{}{
+				"message": "Ingredient not found",
+			})
+		}
+		if errors.Is(err, services.ErrInsufficientStock) {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message": "Stock cannot go below zero",
+			})
+		}
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Stock adjusted successfully",
+		"data":    movements,
+	})
+}
+
+func (i *IngredientController) GetMovements(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	movements := []models.StockMovements{}
+	if err := facades.Orm().Query().Where("ingredient_id = ?", id).Order("created_at desc").Find(&movements); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Stock movements fetched successfully",
+		"data":    movements,
+	})
+}
+
+// parseExpiryDate reads the optional expires_at input (YYYY-MM-DD). The batch
+// is usable until the end of that day.
+func parseExpiryDate(ctx http.Context) (*time.Time, bool) {
+	expiresAtStr := ctx.Request().Input("expires_at")
+	if expiresAtStr == "" {
+		return nil, true
+	}
+
+	expiresAt, err := time.ParseInLocation("2006-01-02", expiresAtStr, time.Local)
+	if err != nil {
+		return nil, false
+	}
+	expiresAt = expiresAt.Add(24*time.Hour - time.Second)
+
+	return &expiresAt, true
+}
+
+func (i *IngredientController) GetBatches(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	query := facades.Orm().Query().Where("ingredient_id = ?", id)
+	if ctx.Request().Query("all") != "true" {
+		query = query.Where("remaining > 0")
+	}
+
+	batches := []models.IngredientBatches{}
+	if err := query.OrderBy("received_at").OrderBy("id").Find(&batches); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Ingredient batches fetched successfully",
+		"data":    batches,
+	})
+}
+
+// GetExpiring lists batches expiring within ?days= days (default 3), including
+// batches that are already past their expiry date.
+func (i *IngredientController) GetExpiring(ctx http.Context) http.Response {
+	days, err := strconv.Atoi(ctx.Request().Query("days", "3"))
+	if err != nil || days < 0 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid days",
+		})
+	}
+
+	batches, err := services.ExpiringBatches(days)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	var totalValue float64
+	for _, batch := range batches {
+		totalValue += batch.Remaining * batch.UnitCost
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message":     "Expiring batches fetched successfully",
+		"data":        batches,
+		"total_value": totalValue,
+	})
+}
+
+// DiscardBatch writes off the remaining quantity of a batch as waste.
+func (i *IngredientController) DiscardBatch(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	userID, err := utils.GetUserIDFromToken(ctx)
+	if err != nil || userID == 0 {
+		return ctx.Response().Json(401, map[string]interface{}{
+			"message": "Unauthorized - user_id not found",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	movements, err := services.DiscardBatch(tx, id, ctx.Request().Input("reason"), userID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, services.ErrBatchNotFound) {
+			return ctx.Response().Json(404, map[string]interface{}{
+				"message": "Batch not found",
+			})
+		}
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Batch discarded successfully",
+		"data":    movements,
+	})
+}
+
+// GetUnits lists the units ingredients, recipes and stock changes can use.
+func (i *IngredientController) GetUnits(ctx http.Context) http.Response {
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Units fetched successfully",
+		"data":    services.Units(),
+	})
+}
+
+// GetForecast projects ingredient usage for the next ?days= days (default 7)
+// from the last ?weeks= weeks of sales (default 8) and suggests reorder
+// quantities.
+func (i *IngredientController) GetForecast(ctx http.Context) http.Response {
+	days, err := strconv.Atoi(ctx.Request().Query("days", "7"))
+	if err != nil || days < 1 || days > 60 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "days must be between 1 and 60",
+		})
+	}
+	weeks, err := strconv.Atoi(ctx.Request().Query("weeks", "8"))
+	if err != nil || weeks < 1 || weeks > 52 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "weeks must be between 1 and 52",
+		})
+	}
+
+	forecast, err := services.ForecastDemand(days, weeks)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Demand forecast generated successfully",
+		"data":    forecast,
+	})
+}
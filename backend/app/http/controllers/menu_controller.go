@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goravel/framework/contracts/http"
+
+	"goravel/app/http/utils"
+	"goravel/app/services"
+)
+
+type MenuController struct {
+}
+
+// Import loads products and variants from a CSV or XLSX file laid out like
+// the export. Rows are matched to existing variants by SKU, then to products
+// by name; unknown categories are created. With dry_run=true the file is only
+// validated. Otherwise nothing is written unless every row is valid.
+// Multipart fields: file, dry_run
+func (m *MenuController) Import(ctx http.Context) http.Response {
+	file, err := ctx.Request().File("file")
+	if err != nil {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "file is required",
+		})
+	}
+	size, err := file.Size()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if size > services.MaxUploadSize() {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message":  "file is too large",
+			"max_size": services.MaxUploadSize(),
+		})
+	}
+
+	data, err := os.ReadFile(file.File())
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	rows, err := services.ReadSpreadsheet(file.GetClientOriginalName(), data)
+	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedSpreadsheet) || errors.Is(err, services.ErrSpreadsheetUnreadable) {
+			return ctx.Response().Json(422, map[string]interface{}{
+				"message": err.Error(),
+			})
+		}
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	plan, result, err := services.PlanMenuImport(rows)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	result.DryRun = ctx.Request().Input("dry_run", ctx.Request().Query("dry_run")) == "true"
+
+	if len(result.Errors) > 0 {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "Import file has invalid rows, nothing was imported",
+			"data":    result,
+		})
+	}
+	if result.DryRun {
+		return ctx.Response().Json(200, map[string]interface{}{
+			"message": "Import file is valid",
+			"data":    result,
+		})
+	}
+
+	var changedBy *int64
+	if userID, err := utils.GetUserIDFromToken(ctx); err == nil {
+		changedBy = &userID
+	}
+	if err := services.ApplyMenuImport(plan, changedBy); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Import failed, nothing was imported",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Menu imported successfully",
+		"data":    result,
+	})
+}
+
+// Export downloads the catalog as ?format=csv (default) or xlsx, in the
+// layout Import accepts.
+func (m *MenuController) Export(ctx http.Context) http.Response {
+	format := strings.ToLower(ctx.Request().Query("format", "csv"))
+	if format != "csv" && format != "xlsx" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message":       "Invalid format",
+			"valid_formats": []string{"csv", "xlsx"},
+		})
+	}
+
+	rows, err := services.MenuExportRows()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	var data []byte
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		data, err = services.WriteXLSX("Menu", rows)
+	} else {
+		data, err = services.WriteCSV(rows)
+	}
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to export menu",
+			"error":   err.Error(),
+		})
+	}
+
+	filename := "menu-" + time.Now().Format("20060102-1504") + "." + format
+	return ctx.Response().
+		Header("Content-Disposition", `attachment; filename="`+filename+`"`).
+		Data(200, contentType, data)
+}
@@ -0,0 +1,518 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type ModifierController struct {
+}
+
+type modifierOptionRequest struct {
+	Name       string   `json:"name"`
+	PriceDelta *float64 `json:"price_delta"`
+	Status     *bool    `json:"status"`
+	SortOrder  int      `json:"sort_order"`
+}
+
+type modifierGroupRequest struct {
+	Name      string                  `json:"name"`
+	MinSelect int                     `json:"min_select"`
+	MaxSelect int                     `json:"max_select"`
+	Required  bool                    `json:"required"`
+	Options   []modifierOptionRequest `json:"options"`
+}
+
+// validateModifierGroupRequest returns a message for the client when the
+// selection rules do not make sense. A max_select of 0 means no limit.
+func validateModifierGroupRequest(req *modifierGroupRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "name is required"
+	}
+	if req.MinSelect < 0 || req.MaxSelect < 0 {
+		return "min_select and max_select cannot be negative"
+	}
+	group := models.ModifierGroups{MinSelect: req.MinSelect, Required: req.Required}
+	if req.MaxSelect > 0 && req.MaxSelect < services.MinSelections(group) {
+		return "max_select cannot be lower than min_select"
+	}
+	for index := range req.Options {
+		if message := validateModifierOptionRequest(&req.Options[index]); message != "" {
+			return message
+		}
+	}
+
+	return ""
+}
+
+func validateModifierOptionRequest(req *modifierOptionRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "option name is required"
+	}
+	if req.PriceDelta == nil {
+		return "price_delta is required"
+	}
+
+	return ""
+}
+
+func modifierGroupOptions(query orm.Query) orm.Query {
+	return query.OrderBy("sort_order").OrderBy("id")
+}
+
+// GetAll - Lấy tất cả nhóm tuỳ chọn (topping, độ cay, món thêm) kèm lựa chọn
+func (m *ModifierController) GetAll(ctx http.Context) http.Response {
+	groups := []models.ModifierGroups{}
+	if err := facades.Orm().Query().With("Options", modifierGroupOptions).OrderBy("name").Find(&groups); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Modifier groups fetched successfully",
+		"data":    groups,
+	})
+}
+
+// Create adds a modifier group together with its options.
+// Body: {"name":"Topping","min_select":0,"max_select":3,"required":false,"options":[{"name":"Trứng","price_delta":5000}]}
+func (m *ModifierController) Create(ctx http.Context) http.Response {
+	var req modifierGroupRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if message := validateModifierGroupRequest(&req); message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	group := models.ModifierGroups{
+		Name:      req.Name,
+		MinSelect: req.MinSelect,
+		MaxSelect: req.MaxSelect,
+		Required:  req.Required,
+	}
+	if err := tx.Create(&group); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	group.Options = []models.ModifierOptions{}
+	for _, optionReq := range req.Options {
+		option := models.ModifierOptions{
+			GroupID:    group.ID,
+			Name:       optionReq.Name,
+			PriceDelta: *optionReq.PriceDelta,
+			Status:     optionReq.Status == nil || *optionReq.Status,
+			SortOrder:  optionReq.SortOrder,
+		}
+		if err := tx.Create(&option); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		group.Options = append(group.Options, option)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Modifier group created successfully",
+		"data":    group,
+	})
+}
+
+// Update changes the group name and selection rules, options are managed
+// through their own endpoints.
+func (m *ModifierController) Update(ctx http.Context) http.Response {
+	groupID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req modifierGroupRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	req.Options = nil
+	if message := validateModifierGroupRequest(&req); message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	var group models.ModifierGroups
+	if err := facades.Orm().Query().Where("id = ?", groupID).First(&group); err != nil || group.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Modifier group not found",
+		})
+	}
+
+	group.Name = req.Name
+	group.MinSelect = req.MinSelect
+	group.MaxSelect = req.MaxSelect
+	group.Required = req.Required
+	if err := facades.Orm().Query().Save(&group); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update modifier group",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Modifier group updated successfully",
+		"data":    group,
+	})
+}
+
+// Delete removes a group that is no longer attached to any product, together
+// with its options and the cart lines that chose them.
+func (m *ModifierController) Delete(ctx http.Context) http.Response {
+	groupID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	productCount, err := facades.Orm().Query().Model(&models.ProductModifierGroups{}).Where("modifier_group_id = ?", groupID).Count()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if productCount > 0 {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Modifier group is attached to products, detach it first",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := services.DeleteCartItems(tx, "id IN (SELECT cart_item_id FROM cart_item_modifiers JOIN modifier_options ON modifier_options.id = cart_item_modifiers.modifier_option_id WHERE modifier_options.group_id = ?)", groupID); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete modifier group",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := tx.Model(&models.ModifierOptions{}).Where("group_id = ?", groupID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete modifier group",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := tx.Model(&models.ModifierGroups{}).Where("id = ?", groupID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete modifier group",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Modifier group deleted successfully",
+	})
+}
+
+// CreateOption - Thêm lựa chọn vào nhóm tuỳ chọn
+func (m *ModifierController) CreateOption(ctx http.Context) http.Response {
+	groupID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req modifierOptionRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if message := validateModifierOptionRequest(&req); message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	var group models.ModifierGroups
+	if err := facades.Orm().Query().Where("id = ?", groupID).First(&group); err != nil || group.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Modifier group not found",
+		})
+	}
+
+	option := models.ModifierOptions{
+		GroupID:    group.ID,
+		Name:       req.Name,
+		PriceDelta: *req.PriceDelta,
+		Status:     req.Status == nil || *req.Status,
+		SortOrder:  req.SortOrder,
+	}
+	if err := facades.Orm().Query().Create(&option); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Modifier option created successfully",
+		"data":    option,
+	})
+}
+
+// UpdateOption changes an option. Carts pick up the new price delta, orders
+// already placed keep the price they were sold at.
+func (m *ModifierController) UpdateOption(ctx http.Context) http.Response {
+	optionID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req modifierOptionRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if message := validateModifierOptionRequest(&req); message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	var option models.ModifierOptions
+	if err := facades.Orm().Query().Where("id = ?", optionID).First(&option); err != nil || option.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Modifier option not found",
+		})
+	}
+
+	option.Name = req.Name
+	option.PriceDelta = *req.PriceDelta
+	option.Status = req.Status == nil || *req.Status
+	option.SortOrder = req.SortOrder
+	if err := facades.Orm().Query().Save(&option); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update modifier option",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Modifier option updated successfully",
+		"data":    option,
+	})
+}
+
+// DeleteOption removes an option and the cart lines that chose it.
+func (m *ModifierController) DeleteOption(ctx http.Context) http.Response {
+	optionID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := services.DeleteCartItems(tx, "id IN (SELECT cart_item_id FROM cart_item_modifiers WHERE modifier_option_id = ?)", optionID); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete modifier option",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := tx.Model(&models.ModifierOptions{}).Where("id = ?", optionID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete modifier option",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Modifier option deleted successfully",
+	})
+}
+
+// SetProductGroups replaces the modifier groups attached to a product, in the
+// order they should be shown.
+// Body: {"modifier_group_ids":[3,1]}
+func (m *ModifierController) SetProductGroups(ctx http.Context) http.Response {
+	type SetProductGroupsRequest struct {
+		ModifierGroupIDs []int64 `json:"modifier_group_ids"`
+	}
+
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req SetProductGroupsRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	var productModel models.Product
+	if err := facades.Orm().Query().Where("id = ?", productID).First(&productModel); err != nil || productModel.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Product not found",
+		})
+	}
+
+	seen := map[int64]bool{}
+	groupIDs := make([]any, 0, len(req.ModifierGroupIDs))
+	for _, groupID := range req.ModifierGroupIDs {
+		if seen[groupID] {
+			return ctx.Response().Json(422, map[string]interface{}{
+				"message": "Each modifier group can only be attached once",
+			})
+		}
+		seen[groupID] = true
+		groupIDs = append(groupIDs, groupID)
+	}
+	if len(groupIDs) > 0 {
+		groupCount, err := facades.Orm().Query().Model(&models.ModifierGroups{}).WhereIn("id", groupIDs).Count()
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		if groupCount != int64(len(groupIDs)) {
+			return ctx.Response().Json(404, map[string]interface{}{
+				"message": "Modifier group not found",
+			})
+		}
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.ProductModifierGroups{}).Where("product_id = ?", productID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update modifier groups",
+			"error":   err.Error(),
+		})
+	}
+	for index, groupID := range req.ModifierGroupIDs {
+		link := models.ProductModifierGroups{
+			ProductID:       productID,
+			ModifierGroupID: groupID,
+			SortOrder:       index,
+		}
+		if err := tx.Create(&link); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to update modifier groups",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	groups, err := services.ProductModifierGroups([]int64{productID})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Modifier groups updated successfully",
+		"data":    groups[productID],
+	})
+}
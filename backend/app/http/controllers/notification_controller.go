@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+type NotificationController struct {
+}
+
+// GetAll - Admin lấy danh sách thông báo, ?unread=true chỉ lấy thông báo chưa đọc
+func (n *NotificationController) GetAll(ctx http.Context) http.Response {
+	query := facades.Orm().Query().Model(&models.AdminNotifications{})
+	if ctx.Request().Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+	if notificationType := ctx.Request().Query("type"); notificationType != "" {
+		query = query.Where("type = ?", notificationType)
+	}
+
+	notifications := []models.AdminNotifications{}
+	if err := query.Order("created_at desc").Find(&notifications); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	unreadCount, err := facades.Orm().Query().Model(&models.AdminNotifications{}).Where("read_at IS NULL").Count()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Notifications fetched successfully",
+		"data":    notifications,
+		"unread":  unreadCount,
+	})
+}
+
+// MarkAsRead - Admin đánh dấu thông báo đã đọc
+func (n *NotificationController) MarkAsRead(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	if _, err := facades.Orm().Query().Model(&models.AdminNotifications{}).Where("id = ? AND read_at IS NULL", id).Update("read_at", time.Now()); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Notification marked as read",
+	})
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"strings"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/goravel/framework/contracts/http"
 
 	"goravel/app/models"
+	"goravel/app/services"
 
 	"strconv"
 
@@ -110,23 +112,113 @@ func (o *OrderController) CreateOrder(ctx http.Context) http.Response {
 
 	// Get cart items
 	var cartItems []models.CartItem
-	if err := facades.Orm().Query().Where("cart_id = ?", cart.ID).With("Product").Find(&cartItems); err != nil {
+	if err := facades.Orm().Query().Where("cart_id = ? AND cart_combo_id IS NULL", cart.ID).With("Product").With("Variant").With("Modifiers.Option.Group").Find(&cartItems); err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
 			"error":   err.Error(),
 		})
 	}
 
-	if len(cartItems) == 0 {
+	cartCombos, err := services.LoadCartCombos(cart.ID)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if len(cartItems) == 0 && len(cartCombos) == 0 {
 		return ctx.Response().Json(400, map[string]interface{}{
 			"message": "Giỏ hàng trống",
 		})
 	}
 
-	// Calculate total
+	// Calculate total, rejecting sizes that were removed after they were added to the cart
 	var total float64
 	for _, item := range cartItems {
-		total += item.Product.Price * float64(item.Quantity)
+		if item.VariantID != nil && (item.Variant == nil || !item.Variant.Status) {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message":    "Kích cỡ sản phẩm trong giỏ hàng không còn bán",
+				"product_id": item.ProductID,
+			})
+		}
+		// Groups may have been changed or unlinked since the item was added
+		optionIDs := make([]int64, 0, len(item.Modifiers))
+		for _, modifier := range item.Modifiers {
+			optionIDs = append(optionIDs, modifier.ModifierOptionID)
+		}
+		if _, err := services.ResolveModifiers(item.ProductID, optionIDs); err != nil {
+			var selectionErr *services.ModifierSelectionError
+			if errors.As(err, &selectionErr) {
+				return ctx.Response().Json(400, map[string]interface{}{
+					"message":    "Lựa chọn thêm của sản phẩm trong giỏ hàng không còn hợp lệ",
+					"error":      selectionErr.Error(),
+					"product_id": item.ProductID,
+				})
+			}
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		total += services.CartItemUnitPrice(item) * float64(item.Quantity)
+	}
+	for _, cartCombo := range cartCombos {
+		if !cartCombo.Combo.Status {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message":  "Combo trong giỏ hàng không còn bán",
+				"combo_id": cartCombo.ComboID,
+			})
+		}
+		for _, item := range cartCombo.Items {
+			if item.ComboSlotOption == nil || item.Product.ID == 0 || !item.Product.Status ||
+				(item.VariantID != nil && (item.Variant == nil || !item.Variant.Status)) {
+				return ctx.Response().Json(400, map[string]interface{}{
+					"message":  "Món trong combo không còn bán",
+					"combo_id": cartCombo.ComboID,
+				})
+			}
+		}
+		total += cartCombo.UnitPrice * float64(cartCombo.Quantity)
+	}
+
+	// Items may have been added before their menu schedule ended
+	productIDs := make([]int64, 0, len(cartItems))
+	for _, item := range cartItems {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	for _, cartCombo := range cartCombos {
+		for _, item := range cartCombo.Items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+	offSchedule, err := services.OffScheduleProductIDs(productIDs, services.RestaurantNow())
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	hidden, err := services.HiddenCategoryProductIDs(productIDs)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	for _, productID := range productIDs {
+		if offSchedule[productID] {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message":    "Sản phẩm trong giỏ hàng không phục vụ vào khung giờ này",
+				"product_id": productID,
+			})
+		}
+		if hidden[productID] {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message":    "Sản phẩm trong giỏ hàng không còn bán",
+				"product_id": productID,
+			})
+		}
 	}
 
 	// Apply voucher if provided
@@ -205,10 +297,15 @@ func (o *OrderController) CreateOrder(ctx http.Context) http.Response {
 	// Create order items
 	for _, item := range cartItems {
 		orderItem := models.OrderItems{
-			OrderID:   order.ID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			UnitPrice: item.Product.Price,
+			OrderID:      order.ID,
+			ProductID:    item.ProductID,
+			VariantID:    item.VariantID,
+			Quantity:     item.Quantity,
+			UnitPrice:    services.CartItemUnitPrice(item),
+			RecipeFactor: services.RecipeFactor(item.Variant),
+		}
+		if item.Variant != nil {
+			orderItem.VariantName = item.Variant.Name
 		}
 		if err := tx.Create(&orderItem); err != nil {
 			tx.Rollback()
@@ -217,6 +314,68 @@ func (o *OrderController) CreateOrder(ctx http.Context) http.Response {
 				"error":   err.Error(),
 			})
 		}
+
+		// Snapshot the chosen modifiers so the kitchen and invoice keep what was ordered
+		for _, modifier := range item.Modifiers {
+			orderItemModifier := models.OrderItemModifiers{
+				OrderItemID:      orderItem.ID,
+				ModifierOptionID: modifier.ModifierOptionID,
+				OptionName:       modifier.Option.Name,
+				PriceDelta:       modifier.Option.PriceDelta,
+			}
+			if modifier.Option.Group != nil {
+				orderItemModifier.GroupName = modifier.Option.Group.Name
+			}
+			if err := tx.Create(&orderItemModifier); err != nil {
+				tx.Rollback()
+				return ctx.Response().Json(500, map[string]interface{}{
+					"message": "Không thể tạo chi tiết đơn hàng",
+					"error":   err.Error(),
+				})
+			}
+		}
+	}
+
+	// Expand combos into their component lines for the kitchen and stock,
+	// charging the bundle price spread over the components
+	for _, cartCombo := range cartCombos {
+		orderCombo := models.OrderCombos{
+			OrderID:   order.ID,
+			ComboID:   cartCombo.ComboID,
+			Name:      cartCombo.Combo.Name,
+			Quantity:  cartCombo.Quantity,
+			UnitPrice: cartCombo.UnitPrice,
+		}
+		if err := tx.Create(&orderCombo); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Không thể tạo chi tiết đơn hàng",
+				"error":   err.Error(),
+			})
+		}
+
+		unitPrices := services.AllocateComboPrice(cartCombo)
+		for index, item := range cartCombo.Items {
+			orderItem := models.OrderItems{
+				OrderID:      order.ID,
+				ProductID:    item.ProductID,
+				VariantID:    item.VariantID,
+				Quantity:     item.Quantity,
+				UnitPrice:    unitPrices[index],
+				RecipeFactor: services.RecipeFactor(item.Variant),
+				OrderComboID: &orderCombo.ID,
+			}
+			if item.Variant != nil {
+				orderItem.VariantName = item.Variant.Name
+			}
+			if err := tx.Create(&orderItem); err != nil {
+				tx.Rollback()
+				return ctx.Response().Json(500, map[string]interface{}{
+					"message": "Không thể tạo chi tiết đơn hàng",
+					"error":   err.Error(),
+				})
+			}
+		}
 	}
 
 	// Create payment record
@@ -246,7 +405,14 @@ func (o *OrderController) CreateOrder(ctx http.Context) http.Response {
 	}
 
 	// Delete cart items from old cart (order_items already saved the info)
-	if _, err := tx.Model(&models.CartItem{}).Where("cart_id = ?", cart.ID).Delete(); err != nil {
+	if err := services.DeleteCartItems(tx, "cart_id = ?", cart.ID); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Không thể xóa cart items",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := tx.Model(&models.CartCombos{}).Where("cart_id = ?", cart.ID).Delete(); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Không thể xóa cart items",
@@ -265,7 +431,7 @@ func (o *OrderController) CreateOrder(ctx http.Context) http.Response {
 
 	// Create new empty cart for user (each user has only 1 active cart)
 	newCart := models.Carts{
-		UserID: userID,
+		UserID: &userID,
 		Status: "active",
 	}
 	if err := tx.Create(&newCart); err != nil {
@@ -343,7 +509,15 @@ func (o *OrderController) GetOrderById(ctx http.Context) http.Response {
 	}
 
 	var orderItems []models.OrderItems
-	if err := facades.Orm().Query().Where("order_id = ?", orderID).With("Product").Find(&orderItems); err != nil {
+	if err := facades.Orm().Query().Where("order_id = ?", orderID).With("Product", withTrashedProducts).With("Modifiers").Find(&orderItems); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	orderCombos := []models.OrderCombos{}
+	if err := facades.Orm().Query().Where("order_id = ?", orderID).Find(&orderCombos); err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
 			"error":   err.Error(),
@@ -358,6 +532,7 @@ func (o *OrderController) GetOrderById(ctx http.Context) http.Response {
 		"data": map[string]interface{}{
 			"order":   order,
 			"items":   orderItems,
+			"combos":  orderCombos,
 			"payment": payment,
 		},
 	})
@@ -436,6 +611,49 @@ func (o *OrderController) UpdateOrderStatus(ctx http.Context) http.Response {
 		})
 	}
 
+	// Re-read the order under lock so two status updates cannot deduct stock twice
+	if err := tx.Model(&models.Orders{}).LockForUpdate().Where("id = ?", orderID).First(&order); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	adminID, _ := getOrderUserIDFromRequest(ctx)
+
+	// Deduct ingredients once the order is confirmed, give them back if it is cancelled
+	stockStatuses := map[string]bool{
+		"confirmed":  true,
+		"preparing":  true,
+		"delivering": true,
+		"completed":  true,
+	}
+	if stockStatuses[status] && !order.StockDeducted {
+		if err := services.DeductOrderStock(tx, orderID, adminID); err != nil {
+			tx.Rollback()
+			if errors.Is(err, services.ErrInsufficientStock) {
+				return ctx.Response().Json(400, map[string]interface{}{
+					"message": "Không đủ nguyên liệu để xác nhận đơn hàng",
+					"error":   err.Error(),
+				})
+			}
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Không thể trừ kho nguyên liệu",
+				"error":   err.Error(),
+			})
+		}
+	}
+	if !stockStatuses[status] && order.StockDeducted {
+		if err := services.RestoreOrderStock(tx, orderID, adminID); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Không thể hoàn kho nguyên liệu",
+				"error":   err.Error(),
+			})
+		}
+	}
+
 	if _, err := tx.Model(&models.Orders{}).Where("id = ?", orderID).Update("status", status); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
@@ -488,8 +706,26 @@ func (o *OrderController) CancelOrder(ctx http.Context) http.Response {
 		})
 	}
 
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	// Read the order under lock so a confirmation racing the cancel cannot
+	// deduct stock that is then never given back
 	var order models.Orders
-	if err := facades.Orm().Query().Where("id = ? AND user_id = ?", orderID, userID).First(&order); err != nil {
+	if err := tx.Model(&models.Orders{}).LockForUpdate().Where("id = ? AND user_id = ?", orderID, userID).First(&order); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if order.ID == 0 {
+		tx.Rollback()
 		return ctx.Response().Json(404, map[string]interface{}{
 			"message": "Đơn hàng không tồn tại",
 		})
@@ -497,19 +733,12 @@ func (o *OrderController) CancelOrder(ctx http.Context) http.Response {
 
 	// Only allow cancelling pending orders
 	if order.Status != "pending" {
+		tx.Rollback()
 		return ctx.Response().Json(400, map[string]interface{}{
 			"message": "Chỉ có thể hủy đơn hàng đang chờ xử lý",
 		})
 	}
 
-	tx, err := facades.Orm().Query().Begin()
-	if err != nil {
-		return ctx.Response().Json(500, map[string]interface{}{
-			"message": "Internal server error",
-			"error":   err.Error(),
-		})
-	}
-
 	if _, err := tx.Model(&models.Orders{}).Where("id = ?", orderID).Update("status", "cancelled"); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
@@ -518,6 +747,16 @@ func (o *OrderController) CancelOrder(ctx http.Context) http.Response {
 		})
 	}
 
+	if order.StockDeducted {
+		if err := services.RestoreOrderStock(tx, orderID, userID); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Không thể hoàn kho nguyên liệu",
+				"error":   err.Error(),
+			})
+		}
+	}
+
 	if _, err := tx.Model(&models.Payment{}).Where("order_id = ?", orderID).Update("status", "cancelled"); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
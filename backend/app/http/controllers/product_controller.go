@@ -1,11 +1,16 @@
 package controllers
 
 import (
+	"github.com/goravel/framework/contracts/database/orm"
 	"github.com/goravel/framework/contracts/http"
 
+	"goravel/app/http/utils"
 	"goravel/app/models"
+	"goravel/app/services"
 
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/goravel/framework/facades"
 )
@@ -80,6 +85,14 @@ func (product *ProductController) Create(ctx http.Context) http.Response {
 		})
 	}
 
+	if err = services.RecordPriceChange(tx, productModel.ID, productModel.Price, services.PriceSourceInitial, nil, productModel.CreatedAt); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
 	if err = tx.Commit(); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
@@ -94,17 +107,216 @@ func (product *ProductController) Create(ctx http.Context) http.Response {
 	})
 }
 
+// GetAll lists products with optional filters:
+// category_id (including its child categories), min_price, max_price, status (true/false), q (name or description),
+// tags (comma separated slugs the product must all carry), exclude_tags (e.g. peanuts,seafood),
+// lang (or the Accept-Language header) for translated content,
+// sort (id, name, price, created_at) with direction (asc/desc), and page/limit.
+// Without page or limit every matching product is returned, as before.
 func (product *ProductController) GetAll(ctx http.Context) http.Response {
+	// Products the kitchen cannot make, that are outside their menu schedule in the
+	// restaurant timezone or whose category is switched off, are hidden unless the
+	// caller asks for them (admin menu)
+	query := facades.Orm().Query().Model(&models.Product{})
+	includeUnavailable := ctx.Request().Query("include_unavailable") == "true"
+	now := services.RestaurantNow()
+	if !includeUnavailable {
+		closedSchedules, err := services.ClosedScheduleIDs(now)
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		query = query.Scopes(services.Sellable, services.OnSchedule(closedSchedules), services.InActiveCategory)
+	}
+
+	if categoryStr := ctx.Request().Query("category_id"); categoryStr != "" {
+		categoryID, err := strconv.ParseInt(categoryStr, 10, 64)
+		if err != nil {
+			return ctx.Response().Json(422, http.Json{
+				"message": "Invalid category_id",
+			})
+		}
+		// Products of child categories are listed under their parent too
+		categoryIDs, err := services.CategoryDescendantIDs(categoryID)
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		ids := make([]any, 0, len(categoryIDs))
+		for _, id := range categoryIDs {
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			ids = append(ids, categoryID)
+		}
+		query = query.WhereIn("category_id", ids)
+	}
+	if minPriceStr := ctx.Request().Query("min_price"); minPriceStr != "" {
+		minPrice, err := strconv.ParseFloat(minPriceStr, 64)
+		if err != nil {
+			return ctx.Response().Json(422, http.Json{
+				"message": "Invalid min_price",
+			})
+		}
+		query = query.Where("price >= ?", minPrice)
+	}
+	if maxPriceStr := ctx.Request().Query("max_price"); maxPriceStr != "" {
+		maxPrice, err := strconv.ParseFloat(maxPriceStr, 64)
+		if err != nil {
+			return ctx.Response().Json(422, http.Json{
+				"message": "Invalid max_price",
+			})
+		}
+		query = query.Where("price <= ?", maxPrice)
+	}
+	if statusStr := ctx.Request().Query("status"); statusStr != "" {
+		status, err := strconv.ParseBool(statusStr)
+		if err != nil {
+			return ctx.Response().Json(422, http.Json{
+				"message": "Invalid status",
+			})
+		}
+		query = query.Where("status = ?", status)
+	}
+	if keyword := strings.TrimSpace(ctx.Request().Query("q")); keyword != "" {
+		// % and _ typed by the user are matched literally
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(keyword) + "%"
+		query = query.Where(`(name ILIKE ? ESCAPE '\' OR description ILIKE ? ESCAPE '\' OR id IN (SELECT product_id FROM product_translations WHERE name ILIKE ? ESCAPE '\' OR description ILIKE ? ESCAPE '\'))`, pattern, pattern, pattern, pattern)
+	}
+	// Allergens inherited from recipe ingredients count for both filters
+	if tagSlugs := services.ParseTagSlugs(ctx.Request().Query("tags")); len(tagSlugs) > 0 {
+		query = query.Scopes(services.WithAllTags(tagSlugs))
+	}
+	if excludeSlugs := services.ParseTagSlugs(ctx.Request().Query("exclude_tags")); len(excludeSlugs) > 0 {
+		query = query.Scopes(services.WithoutTags(excludeSlugs))
+	}
+
+	sortColumns := map[string]bool{"id": true, "name": true, "price": true, "created_at": true}
+	sortField := ctx.Request().Query("sort", "id")
+	if !sortColumns[sortField] {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message":     "Invalid sort field",
+			"sort_fields": []string{"id", "name", "price", "created_at"},
+		})
+	}
+	direction := strings.ToLower(ctx.Request().Query("direction", "asc"))
+	if direction != "asc" && direction != "desc" {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid direction, use asc or desc",
+		})
+	}
+	query = query.OrderBy(sortField, direction)
+	if sortField != "id" {
+		query = query.OrderBy("id")
+	}
+
+	query = query.With("Variants", activeVariants)
+
 	products := []models.Product{}
-	if err := facades.Orm().Query().Model(&models.Product{}).Find(&products); err != nil {
+	var total int64
+	paginated := ctx.Request().Query("page") != "" || ctx.Request().Query("limit") != ""
+	page, limit := 1, 0
+	if paginated {
+		var err error
+		if page, err = strconv.Atoi(ctx.Request().Query("page", "1")); err != nil || page < 1 {
+			return ctx.Response().Json(422, http.Json{
+				"message": "Invalid page",
+			})
+		}
+		if limit, err = strconv.Atoi(ctx.Request().Query("limit", "20")); err != nil || limit < 1 || limit > 100 {
+			return ctx.Response().Json(422, http.Json{
+				"message": "limit must be between 1 and 100",
+			})
+		}
+		if err := query.Paginate(page, limit, &products, &total); err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+	} else {
+		if err := query.Find(&products); err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		total = int64(len(products))
+		limit = len(products)
+	}
+
+	unavailable := map[int64]bool{}
+	if includeUnavailable {
+		var err error
+		if unavailable, err = services.UnavailableProductIDs(); err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+	}
+	productIDs := make([]int64, 0, len(products))
+	for i := range products {
+		productIDs = append(productIDs, products[i].ID)
+	}
+	offSchedule := map[int64]bool{}
+	if includeUnavailable {
+		var err error
+		if offSchedule, err = services.OffScheduleProductIDs(productIDs, now); err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+	}
+	for i := range products {
+		products[i].Available = !unavailable[products[i].ID] && !offSchedule[products[i].ID]
+	}
+
+	modifierGroups, err := services.ProductModifierGroups(productIDs)
+	if err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
 			"error":   err.Error(),
 		})
 	}
-	return ctx.Response().Json(200, map[string]interface{}{
+	tags, err := services.ProductTags(productIDs)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	for i := range products {
+		products[i].ModifierGroups = modifierGroups[products[i].ID]
+		products[i].Tags = tags[products[i].ID]
+	}
+
+	locale := utils.GetLocale(ctx)
+	if err := services.TranslateProducts(products, locale); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	totalPages := 1
+	if limit > 0 {
+		totalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+	return ctx.Response().Header("Content-Language", locale).Json(200, map[string]interface{}{
 		"message": "Products fetched successfully",
 		"data":    products,
+		"pagination": map[string]interface{}{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+		},
 	})
 }
 
@@ -112,16 +324,95 @@ func (product *ProductController) GetById(ctx http.Context) http.Response {
 	var err error
 
 	productModel := models.Product{}
-	if err = facades.Orm().Query().Model(&models.Product{}).Where("id", ctx.Request().Input("id")).First(&productModel); err != nil {
+	if err = facades.Orm().Query().Model(&models.Product{}).Where("id", ctx.Request().Input("id")).With("Variants", activeVariants).With("Nutrition").First(&productModel); err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
 			"err":     err.Error(),
 		})
 	}
 
-	return ctx.Response().Json(200, map[string]interface{}{
+	if productModel.Available, err = services.CanMakeProduct(productModel.ID, 1); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"err":     err.Error(),
+		})
+	}
+	if productModel.Available {
+		if productModel.Available, err = services.ProductOnSchedule(productModel.ID, services.RestaurantNow()); err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"err":     err.Error(),
+			})
+		}
+	}
+
+	modifierGroups, err := services.ProductModifierGroups([]int64{productModel.ID})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"err":     err.Error(),
+		})
+	}
+	productModel.ModifierGroups = modifierGroups[productModel.ID]
+
+	tags, err := services.ProductTags([]int64{productModel.ID})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"err":     err.Error(),
+		})
+	}
+	productModel.Tags = tags[productModel.ID]
+	if productModel.Nutrition != nil {
+		for i := range productModel.Variants {
+			nutrition := services.ScaleNutrition(*productModel.Nutrition, services.RecipeFactor(&productModel.Variants[i]))
+			productModel.Variants[i].Nutrition = &nutrition
+		}
+	}
+
+	locale := utils.GetLocale(ctx)
+	translated := []models.Product{productModel}
+	if err = services.TranslateProducts(translated, locale); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"err":     err.Error(),
+		})
+	}
+
+	return ctx.Response().Header("Content-Language", locale).Json(200, map[string]interface{}{
 		"message": "Product fetched successfully",
-		"data":    productModel,
+		"data":    translated[0],
+	})
+}
+
+// GetRecommendations lists products often bought together with this one,
+// learnt from past orders. limit defaults to 6.
+func (product *ProductController) GetRecommendations(ctx http.Context) http.Response {
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+	limit, err := strconv.Atoi(ctx.Request().Query("limit", "6"))
+	if err != nil || limit < 1 || limit > 20 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "limit must be between 1 and 20",
+		})
+	}
+
+	locale := utils.GetLocale(ctx)
+	recommendations, err := services.RecommendProducts([]int64{productID}, nil, limit, locale)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"err":     err.Error(),
+		})
+	}
+
+	return ctx.Response().Header("Content-Language", locale).Json(200, map[string]interface{}{
+		"message": "Recommendations fetched successfully",
+		"data":    recommendations,
 	})
 }
 
@@ -158,13 +449,30 @@ func (product *ProductController) Remove(ctx http.Context) http.Response {
 			"error":   err.Error(),
 		})
 	}
-	if _, err = tx.Model(&models.Product{}).Where("id", id).Delete(); err != nil {
+	// Products are soft deleted so past order lines still point at them
+	result, err := tx.Model(&models.Product{}).Where("id", id).Delete()
+	if err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete Product",
+			"error":   err.Error(),
+		})
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Product not found",
+		})
+	}
+	if err = services.DeleteCartItems(tx, "product_id = ? AND cart_combo_id IS NULL", id); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Failed to delete Product",
 			"error":   err.Error(),
 		})
 	}
+	// Combo slot options are kept for Restore: the soft delete scope already
+	// keeps a trashed dish out of ActiveCombo and checkout
 	if err = tx.Commit(); err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
@@ -177,6 +485,58 @@ func (product *ProductController) Remove(ctx http.Context) http.Response {
 	})
 }
 
+// GetTrash - Admin lấy danh sách sản phẩm đã xoá
+func (product *ProductController) GetTrash(ctx http.Context) http.Response {
+	products := []models.Product{}
+	if err := facades.Orm().Query().WithTrashed().Where("deleted_at IS NOT NULL").OrderBy("deleted_at", "desc").Find(&products); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Deleted products fetched successfully",
+		"data":    products,
+	})
+}
+
+// Restore - Admin khôi phục sản phẩm đã xoá
+func (product *ProductController) Restore(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	result, err := facades.Orm().Query().WithTrashed().Model(&models.Product{}).Where("id = ? AND deleted_at IS NOT NULL", id).Restore()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to restore Product",
+			"error":   err.Error(),
+		})
+	}
+	if result.RowsAffected == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Deleted product not found",
+		})
+	}
+
+	var productModel models.Product
+	if err := facades.Orm().Query().Where("id = ?", id).First(&productModel); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Product restored successfully",
+		"data":    productModel,
+	})
+}
+
 func (product *ProductController) Update(ctx http.Context) http.Response {
 	var err error
 	validator, err := ctx.Request().Validate(map[string]string{
@@ -236,6 +596,21 @@ func (product *ProductController) Update(ctx http.Context) http.Response {
 		Status:      status, // bool
 	}
 
+	var current models.Product
+	if err := tx.Model(&models.Product{}).LockForUpdate().Where("id", id).First(&current); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"err":     err.Error(),
+		})
+	}
+	if current.ID == 0 {
+		tx.Rollback()
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Product not found",
+		})
+	}
+
 	if _, err := tx.Model(&models.Product{}).Where("id", id).Update(&productUpdate); err != nil {
 		tx.Rollback()
 		return ctx.Response().Json(500, map[string]interface{}{
@@ -244,6 +619,21 @@ func (product *ProductController) Update(ctx http.Context) http.Response {
 		})
 	}
 
+	// Giữ lại lịch sử giá khi admin đổi giá trực tiếp
+	if current.Price != price {
+		var changedBy *int64
+		if userID, err := utils.GetUserIDFromToken(ctx); err == nil {
+			changedBy = &userID
+		}
+		if err := services.RecordPriceChange(tx, id, price, services.PriceSourceManual, changedBy, time.Now()); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Update product failed",
+				"err":     err.Error(),
+			})
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return ctx.Response().Json(500, map[string]interface{}{
 			"message": "Internal server error",
@@ -310,6 +700,13 @@ func (product *ProductController) AddProducts(ctx http.Context) http.Response {
 			})
 		}
 
+		if err = services.RecordPriceChange(tx, productModel.ID, productModel.Price, services.PriceSourceInitial, nil, productModel.CreatedAt); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
 	}
 	if err = tx.Commit(); err != nil {
 		tx.Rollback()
@@ -324,3 +721,643 @@ func (product *ProductController) AddProducts(ctx http.Context) http.Response {
 	})
 
 }
+
+// GetRecipe - Lấy công thức (định lượng nguyên liệu) của sản phẩm
+func (product *ProductController) GetRecipe(ctx http.Context) http.Response {
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	recipe := []models.ProductIngredient{}
+	if err := facades.Orm().Query().Where("product_id = ?", productID).With("Ingredient").Find(&recipe); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Recipe fetched successfully",
+		"data":    recipe,
+	})
+}
+
+// UpdateRecipe - Thay toàn bộ công thức của sản phẩm bằng danh sách gửi lên.
+// amount_used may be given in any unit of the ingredient's dimension and is
+// stored in the ingredient's own unit.
+func (product *ProductController) UpdateRecipe(ctx http.Context) http.Response {
+	type RecipeItem struct {
+		IngredientID int64   `json:"ingredient_id"`
+		AmountUsed   float64 `json:"amount_used"`
+		Unit         string  `json:"unit"`
+	}
+	type RecipeRequest struct {
+		Ingredients []RecipeItem `json:"ingredients"`
+	}
+
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req RecipeRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	var productModel models.Product
+	if err := facades.Orm().Query().Where("id = ?", productID).First(&productModel); err != nil || productModel.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Product not found",
+		})
+	}
+
+	seen := map[int64]bool{}
+	ingredientIDs := make([]any, 0, len(req.Ingredients))
+	for _, item := range req.Ingredients {
+		if item.IngredientID <= 0 || item.AmountUsed <= 0 {
+			return ctx.Response().Json(422, map[string]interface{}{
+				"message": "ingredient_id and amount_used must be greater than 0",
+			})
+		}
+		if seen[item.IngredientID] {
+			return ctx.Response().Json(422, map[string]interface{}{
+				"message":       "Duplicate ingredient in recipe",
+				"ingredient_id": item.IngredientID,
+			})
+		}
+		seen[item.IngredientID] = true
+		ingredientIDs = append(ingredientIDs, item.IngredientID)
+	}
+
+	if len(ingredientIDs) > 0 {
+		var ingredients []models.Ingredients
+		if err := facades.Orm().Query().WhereIn("id", ingredientIDs).Find(&ingredients); err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		if len(ingredients) != len(ingredientIDs) {
+			return ctx.Response().Json(422, map[string]interface{}{
+				"message": "Some ingredients do not exist",
+			})
+		}
+
+		units := map[int64]string{}
+		for _, ingredient := range ingredients {
+			units[ingredient.ID] = ingredient.Unit
+		}
+		for index, item := range req.Ingredients {
+			amountUsed, err := services.ConvertQuantity(item.AmountUsed, item.Unit, units[item.IngredientID])
+			if err != nil {
+				return ctx.Response().Json(422, map[string]interface{}{
+					"message":       err.Error(),
+					"ingredient_id": item.IngredientID,
+				})
+			}
+			req.Ingredients[index].AmountUsed = amountUsed
+		}
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.ProductIngredient{}).Where("product_id = ?", productID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update recipe",
+			"error":   err.Error(),
+		})
+	}
+
+	recipe := make([]models.ProductIngredient, 0, len(req.Ingredients))
+	for _, item := range req.Ingredients {
+		recipeItem := models.ProductIngredient{
+			ProductID:    productID,
+			IngredientID: item.IngredientID,
+			AmountUsed:   item.AmountUsed,
+		}
+		if err := tx.Create(&recipeItem); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to update recipe",
+				"error":   err.Error(),
+			})
+		}
+		recipe = append(recipe, recipeItem)
+	}
+
+	if err := services.RecalculateProductNutrition(tx, []int64{productID}); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update recipe",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Recipe updated successfully",
+		"data":    recipe,
+	})
+}
+
+// withTrashedProducts preloads products even when they have been deleted,
+// for order history and reports.
+func withTrashedProducts(query orm.Query) orm.Query {
+	return query.WithTrashed()
+}
+
+// activeVariants preloads only the variants customers can pick, in menu order.
+func activeVariants(query orm.Query) orm.Query {
+	return query.Where("status = ?", true).OrderBy("sort_order").OrderBy("id")
+}
+
+type variantRequest struct {
+	Name         string   `json:"name"`
+	SKU          string   `json:"sku"`
+	Price        *float64 `json:"price"`
+	RecipeFactor float64  `json:"recipe_factor"`
+	IsDefault    bool     `json:"is_default"`
+	Status       *bool    `json:"status"`
+	SortOrder    int      `json:"sort_order"`
+}
+
+// validateVariantRequest returns a message for the client when the request is
+// invalid. variantID is the variant being updated, 0 when creating.
+func validateVariantRequest(req *variantRequest, variantID int64) (string, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.SKU = strings.TrimSpace(req.SKU)
+	if req.Name == "" || req.SKU == "" {
+		return "name and sku are required", nil
+	}
+	if req.Price == nil || *req.Price < 0 {
+		return "price is required and cannot be negative", nil
+	}
+	if req.RecipeFactor < 0 {
+		return "recipe_factor cannot be negative", nil
+	}
+	if req.RecipeFactor == 0 {
+		req.RecipeFactor = 1
+	}
+
+	skuCount, err := facades.Orm().Query().Model(&models.ProductVariants{}).Where("sku = ? AND id <> ?", req.SKU, variantID).Count()
+	if err != nil {
+		return "", err
+	}
+	if skuCount > 0 {
+		return "SKU is already used", nil
+	}
+
+	return "", nil
+}
+
+// clearDefaultVariant makes sure a product has at most one default variant.
+func clearDefaultVariant(tx orm.Query, productID, keepID int64) error {
+	_, err := tx.Model(&models.ProductVariants{}).Where("product_id = ? AND id <> ?", productID, keepID).Update("is_default", false)
+	return err
+}
+
+// GetVariants - Lấy tất cả kích cỡ của sản phẩm, kể cả đã ngừng bán
+func (product *ProductController) GetVariants(ctx http.Context) http.Response {
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	variants := []models.ProductVariants{}
+	if err := facades.Orm().Query().Where("product_id = ?", productID).OrderBy("sort_order").OrderBy("id").Find(&variants); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Variants fetched successfully",
+		"data":    variants,
+	})
+}
+
+// CreateVariant - Thêm kích cỡ (S/M/L, suất thường/suất lớn) cho sản phẩm
+func (product *ProductController) CreateVariant(ctx http.Context) http.Response {
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req variantRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	var productModel models.Product
+	if err := facades.Orm().Query().Where("id = ?", productID).First(&productModel); err != nil || productModel.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Product not found",
+		})
+	}
+
+	message, err := validateVariantRequest(&req, 0)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	variant := models.ProductVariants{
+		ProductID:    productID,
+		Name:         req.Name,
+		SKU:          req.SKU,
+		Price:        *req.Price,
+		RecipeFactor: req.RecipeFactor,
+		IsDefault:    req.IsDefault,
+		Status:       req.Status == nil || *req.Status,
+		SortOrder:    req.SortOrder,
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Create(&variant); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if variant.IsDefault {
+		if err := clearDefaultVariant(tx, productID, variant.ID); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Variant created successfully",
+		"data":    variant,
+	})
+}
+
+func (product *ProductController) UpdateVariant(ctx http.Context) http.Response {
+	variantID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req variantRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	var variant models.ProductVariants
+	if err := facades.Orm().Query().Where("id = ?", variantID).First(&variant); err != nil || variant.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Variant not found",
+		})
+	}
+
+	message, err := validateVariantRequest(&req, variantID)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	variant.Name = req.Name
+	variant.SKU = req.SKU
+	variant.Price = *req.Price
+	variant.RecipeFactor = req.RecipeFactor
+	variant.IsDefault = req.IsDefault
+	variant.Status = req.Status == nil || *req.Status
+	variant.SortOrder = req.SortOrder
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.ProductVariants{}).Where("id = ?", variantID).Update(map[string]interface{}{
+		"name":          variant.Name,
+		"sku":           variant.SKU,
+		"price":         variant.Price,
+		"recipe_factor": variant.RecipeFactor,
+		"is_default":    variant.IsDefault,
+		"status":        variant.Status,
+		"sort_order":    variant.SortOrder,
+	}); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update variant",
+			"error":   err.Error(),
+		})
+	}
+	if variant.IsDefault {
+		if err := clearDefaultVariant(tx, variant.ProductID, variant.ID); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Variant updated successfully",
+		"data":    variant,
+	})
+}
+
+// DeleteVariant takes a variant off the menu and removes the cart lines and
+// cart combos that use it. The row is kept inactive so orders placed with it
+// still resolve.
+func (product *ProductController) DeleteVariant(ctx http.Context) http.Response {
+	variantID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := services.DeleteCartItems(tx, "variant_id = ? AND cart_combo_id IS NULL", variantID); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete variant",
+			"error":   err.Error(),
+		})
+	}
+	// A combo missing one of its components would still be charged in full
+	if err := services.DeleteCartCombos(tx, "id IN (SELECT cart_combo_id FROM cart_items WHERE variant_id = ? AND cart_combo_id IS NOT NULL)", variantID); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete variant",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.ProductVariants{}).Where("id = ?", variantID).Update(map[string]interface{}{
+		"status":     false,
+		"is_default": false,
+	}); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete variant",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Variant deleted successfully",
+	})
+}
+
+// GetPriceHistory - Lịch sử giá của sản phẩm, mới nhất trước
+func (product *ProductController) GetPriceHistory(ctx http.Context) http.Response {
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var history []models.ProductPriceHistory
+	if err := facades.Orm().Query().Where("product_id = ?", productID).Order("started_at desc").Order("id desc").Find(&history); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Get price history successfully",
+		"data":    history,
+	})
+}
+
+// GetPriceChanges - Các lần đổi giá đã lên lịch của sản phẩm, có thể lọc theo ?status=
+func (product *ProductController) GetPriceChanges(ctx http.Context) http.Response {
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	query := facades.Orm().Query().Where("product_id = ?", productID)
+	if status := ctx.Request().Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var changes []models.ProductPriceChanges
+	if err := query.Order("effective_at desc").Order("id desc").Find(&changes); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Get price changes successfully",
+		"data":    changes,
+	})
+}
+
+// SchedulePrice - Lên lịch giá mới cho sản phẩm. effective_at nhận RFC3339
+// hoặc "YYYY-MM-DD HH:MM" theo múi giờ nhà hàng và phải ở tương lai.
+func (product *ProductController) SchedulePrice(ctx http.Context) http.Response {
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	type scheduleRequest struct {
+		Price       float64 `json:"price" form:"price"`
+		EffectiveAt string  `json:"effective_at" form:"effective_at"`
+		Note        string  `json:"note" form:"note"`
+	}
+	var req scheduleRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if req.Price <= 0 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid price",
+		})
+	}
+
+	effectiveAt, err := time.Parse(time.RFC3339, req.EffectiveAt)
+	if err != nil {
+		effectiveAt, err = time.ParseInLocation("2006-01-02 15:04", req.EffectiveAt, services.RestaurantLocation())
+	}
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid effective_at. Please use RFC3339 or YYYY-MM-DD HH:MM",
+		})
+	}
+	if !effectiveAt.After(time.Now()) {
+		return ctx.Response().Json(422, http.Json{
+			"message": "effective_at must be in the future",
+		})
+	}
+
+	var productModel models.Product
+	if err := facades.Orm().Query().Where("id = ?", productID).First(&productModel); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if productModel.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Product not found",
+		})
+	}
+
+	userID, err := utils.GetUserIDFromToken(ctx)
+	if err != nil {
+		return ctx.Response().Json(401, map[string]interface{}{
+			"message": "Unauthorized",
+		})
+	}
+
+	change := models.ProductPriceChanges{
+		ProductID:   productID,
+		Price:       req.Price,
+		EffectiveAt: effectiveAt,
+		Status:      services.PriceChangePending,
+		Note:        req.Note,
+		CreatedBy:   userID,
+	}
+	if err := facades.Orm().Query().Create(&change); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to schedule price change",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Price change scheduled successfully",
+		"data":    change,
+	})
+}
+
+// CancelPriceChange - Huỷ một lần đổi giá chưa được áp dụng
+func (product *ProductController) CancelPriceChange(ctx http.Context) http.Response {
+	changeID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	result, err := facades.Orm().Query().Model(&models.ProductPriceChanges{}).
+		Where("id = ? AND status = ?", changeID, services.PriceChangePending).
+		Update("status", services.PriceChangeCancelled)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if result.RowsAffected == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Pending price change not found",
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Price change cancelled successfully",
+	})
+}
@@ -0,0 +1,593 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/contracts/mail"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/http/utils"
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type PurchaseOrderController struct {
+}
+
+type purchaseOrderLineRequest struct {
+	IngredientID int64   `json:"ingredient_id"`
+	Quantity     float64 `json:"quantity"`
+	UnitCost     float64 `json:"unit_cost"`
+	Unit         string  `json:"unit"`
+}
+
+type purchaseOrderRequest struct {
+	SupplierID int64                      `json:"supplier_id"`
+	Note       string                     `json:"note"`
+	Lines      []purchaseOrderLineRequest `json:"lines"`
+}
+
+// validatePurchaseOrderRequest checks the supplier and lines, returning a
+// message for the client when the request is invalid. Lines ordered in another
+// unit are converted to the ingredient's unit in place.
+func validatePurchaseOrderRequest(req *purchaseOrderRequest) (string, error) {
+	if req.SupplierID <= 0 {
+		return "supplier_id is required", nil
+	}
+	if len(req.Lines) == 0 {
+		return "Purchase order needs at least one line", nil
+	}
+
+	var supplier models.Suppliers
+	if err := facades.Orm().Query().Where("id = ?", req.SupplierID).First(&supplier); err != nil {
+		return "", err
+	}
+	if supplier.ID == 0 || !supplier.IsActive {
+		return "Supplier not found or inactive", nil
+	}
+
+	ingredientIDs := make([]any, 0, len(req.Lines))
+	seen := map[int64]bool{}
+	for _, line := range req.Lines {
+		if line.IngredientID <= 0 || line.Quantity <= 0 || line.UnitCost < 0 {
+			return "Each line needs an ingredient_id, a positive quantity and a unit_cost", nil
+		}
+		if seen[line.IngredientID] {
+			return "Duplicate ingredient in purchase order", nil
+		}
+		seen[line.IngredientID] = true
+		ingredientIDs = append(ingredientIDs, line.IngredientID)
+	}
+
+	var ingredients []models.Ingredients
+	if err := facades.Orm().Query().WhereIn("id", ingredientIDs).Find(&ingredients); err != nil {
+		return "", err
+	}
+	if len(ingredients) != len(ingredientIDs) {
+		return "Some ingredients do not exist", nil
+	}
+
+	units := map[int64]string{}
+	for _, ingredient := range ingredients {
+		units[ingredient.ID] = ingredient.Unit
+	}
+	for index, line := range req.Lines {
+		ratio, err := services.ConvertQuantity(1, line.Unit, units[line.IngredientID])
+		if err != nil {
+			return err.Error(), nil
+		}
+		req.Lines[index].Quantity = line.Quantity * ratio
+		req.Lines[index].UnitCost = line.UnitCost / ratio
+	}
+
+	return "", nil
+}
+
+// createPurchaseOrderLines stores the lines and returns the order total.
+func createPurchaseOrderLines(tx orm.Query, purchaseOrderID int64, lines []purchaseOrderLineRequest) (float64, error) {
+	var total float64
+	for _, line := range lines {
+		orderLine := models.PurchaseOrderLines{
+			PurchaseOrderID: purchaseOrderID,
+			IngredientID:    line.IngredientID,
+			Quantity:        line.Quantity,
+			UnitCost:        line.UnitCost,
+		}
+		if err := tx.Create(&orderLine); err != nil {
+			return 0, err
+		}
+		total += line.Quantity * line.UnitCost
+	}
+
+	return total, nil
+}
+
+func findPurchaseOrder(ctx http.Context) (models.PurchaseOrders, error) {
+	var purchaseOrder models.PurchaseOrders
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return purchaseOrder, nil
+	}
+	err = facades.Orm().Query().Where("id = ?", id).With("Supplier").With("Lines.Ingredient").First(&purchaseOrder)
+
+	return purchaseOrder, err
+}
+
+func (p *PurchaseOrderController) Create(ctx http.Context) http.Response {
+	var req purchaseOrderRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	message, err := validatePurchaseOrderRequest(&req)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	userID, err := utils.GetUserIDFromToken(ctx)
+	if err != nil || userID == 0 {
+		return ctx.Response().Json(401, map[string]interface{}{
+			"message": "Unauthorized - user_id not found",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	purchaseOrder := models.PurchaseOrders{
+		SupplierID: req.SupplierID,
+		Status:     services.PurchaseOrderDraft,
+		Note:       req.Note,
+		CreatedBy:  userID,
+	}
+	if err := tx.Create(&purchaseOrder); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	total, err := createPurchaseOrderLines(tx, purchaseOrder.ID, req.Lines)
+	if err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := tx.Model(&models.PurchaseOrders{}).Where("id = ?", purchaseOrder.ID).Update("total", total); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	purchaseOrder.Total = total
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Purchase order created successfully",
+		"data":    purchaseOrder,
+	})
+}
+
+func (p *PurchaseOrderController) GetAll(ctx http.Context) http.Response {
+	query := facades.Orm().Query().Model(&models.PurchaseOrders{})
+	if status := ctx.Request().Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if supplierID := ctx.Request().Query("supplier_id"); supplierID != "" {
+		query = query.Where("supplier_id = ?", supplierID)
+	}
+
+	purchaseOrders := []models.PurchaseOrders{}
+	if err := query.With("Supplier").Order("created_at desc").Find(&purchaseOrders); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Purchase orders fetched successfully",
+		"data":    purchaseOrders,
+	})
+}
+
+func (p *PurchaseOrderController) GetById(ctx http.Context) http.Response {
+	purchaseOrder, err := findPurchaseOrder(ctx)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if purchaseOrder.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Purchase order not found",
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Purchase order fetched successfully",
+		"data":    purchaseOrder,
+	})
+}
+
+// Update replaces the supplier, note and lines of a draft purchase order.
+func (p *PurchaseOrderController) Update(ctx http.Context) http.Response {
+	purchaseOrder, err := findPurchaseOrder(ctx)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if purchaseOrder.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Purchase order not found",
+		})
+	}
+	if purchaseOrder.Status != services.PurchaseOrderDraft {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Only draft purchase orders can be edited",
+		})
+	}
+
+	var req purchaseOrderRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	message, err := validatePurchaseOrderRequest(&req)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.PurchaseOrderLines{}).Where("purchase_order_id = ?", purchaseOrder.ID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	total, err := createPurchaseOrderLines(tx, purchaseOrder.ID, req.Lines)
+	if err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.PurchaseOrders{}).Where("id = ?", purchaseOrder.ID).Update(map[string]interface{}{
+		"supplier_id": req.SupplierID,
+		"note":        req.Note,
+		"total":       total,
+	}); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Purchase order updated successfully",
+	})
+}
+
+// Send marks a draft purchase order as sent and emails it to the supplier
+// when the supplier has an email address.
+func (p *PurchaseOrderController) Send(ctx http.Context) http.Response {
+	purchaseOrder, err := findPurchaseOrder(ctx)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if purchaseOrder.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Purchase order not found",
+		})
+	}
+	if purchaseOrder.Status != services.PurchaseOrderDraft {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Only draft purchase orders can be sent",
+		})
+	}
+
+	now := time.Now()
+	result, err := facades.Orm().Query().Model(&models.PurchaseOrders{}).Where("id = ? AND status = ?", purchaseOrder.ID, services.PurchaseOrderDraft).Update(map[string]interface{}{
+		"status":  services.PurchaseOrderSent,
+		"sent_at": now,
+	})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if result.RowsAffected == 0 {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Only draft purchase orders can be sent",
+		})
+	}
+
+	emailed := false
+	if purchaseOrder.Supplier.Email != "" {
+		var body strings.Builder
+		body.WriteString(fmt.Sprintf("<p>Purchase order #%d</p><table><tr><th>Ingredient</th><th>Quantity</th><th>Unit cost</th></tr>", purchaseOrder.ID))
+		for _, line := range purchaseOrder.Lines {
+			body.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%g %s</td><td>%.2f</td></tr>", line.Ingredient.Name, line.Quantity, line.Ingredient.Unit, line.UnitCost))
+		}
+		body.WriteString(fmt.Sprintf("</table><p>Total: %.2f</p><p>%s</p>", purchaseOrder.Total, purchaseOrder.Note))
+
+		if err := facades.Mail().To([]string{purchaseOrder.Supplier.Email}).
+			Subject(fmt.Sprintf("Purchase order #%d", purchaseOrder.ID)).
+			Content(mail.Content{Html: body.String()}).
+			Send(); err != nil {
+			facades.Log().Errorf("purchase order mail error: %v", err)
+		} else {
+			emailed = true
+		}
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Purchase order sent successfully",
+		"data": map[string]interface{}{
+			"id":      purchaseOrder.ID,
+			"status":  services.PurchaseOrderSent,
+			"sent_at": now,
+			"emailed": emailed,
+		},
+	})
+}
+
+// Receive books a sent purchase order into stock. Lines may carry the
+// actually delivered quantity and invoiced unit cost; missing lines are
+// received as ordered.
+func (p *PurchaseOrderController) Receive(ctx http.Context) http.Response {
+	type ReceiveLine struct {
+		ID               int64    `json:"id"`
+		ReceivedQuantity *float64 `json:"received_quantity"`
+		UnitCost         *float64 `json:"unit_cost"`
+		ExpiresAt        string   `json:"expires_at"`
+	}
+	type ReceiveRequest struct {
+		Lines []ReceiveLine `json:"lines"`
+	}
+
+	var req ReceiveRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	userID, err := utils.GetUserIDFromToken(ctx)
+	if err != nil || userID == 0 {
+		return ctx.Response().Json(401, map[string]interface{}{
+			"message": "Unauthorized - user_id not found",
+		})
+	}
+
+	purchaseOrder, err := findPurchaseOrder(ctx)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if purchaseOrder.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Purchase order not found",
+		})
+	}
+	if purchaseOrder.Status != services.PurchaseOrderSent {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Only sent purchase orders can be received",
+		})
+	}
+
+	received := map[int64]ReceiveLine{}
+	expiries := map[int64]*time.Time{}
+	for _, line := range req.Lines {
+		if (line.ReceivedQuantity != nil && *line.ReceivedQuantity < 0) || (line.UnitCost != nil && *line.UnitCost < 0) {
+			return ctx.Response().Json(422, map[string]interface{}{
+				"message": "received_quantity and unit_cost cannot be negative",
+			})
+		}
+		if line.ExpiresAt != "" {
+			expiresAt, err := time.ParseInLocation("2006-01-02", line.ExpiresAt, time.Local)
+			if err != nil {
+				return ctx.Response().Json(422, map[string]interface{}{
+					"message": "Invalid expires_at, please use YYYY-MM-DD",
+				})
+			}
+			expiresAt = expiresAt.Add(24*time.Hour - time.Second)
+			expiries[line.ID] = &expiresAt
+		}
+		received[line.ID] = line
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	// Lock the order so a double submit cannot book the delivery twice
+	var locked models.PurchaseOrders
+	if err := tx.Model(&models.PurchaseOrders{}).LockForUpdate().Where("id = ?", purchaseOrder.ID).First(&locked); err != nil || locked.Status != services.PurchaseOrderSent {
+		tx.Rollback()
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Only sent purchase orders can be received",
+		})
+	}
+
+	var total float64
+	for _, line := range purchaseOrder.Lines {
+		line.ReceivedQuantity = line.Quantity
+		if input, ok := received[line.ID]; ok {
+			if input.ReceivedQuantity != nil {
+				line.ReceivedQuantity = *input.ReceivedQuantity
+			}
+			if input.UnitCost != nil {
+				line.UnitCost = *input.UnitCost
+			}
+		}
+
+		if _, err := tx.Model(&models.PurchaseOrderLines{}).Where("id = ?", line.ID).Update(map[string]interface{}{
+			"received_quantity": line.ReceivedQuantity,
+			"unit_cost":         line.UnitCost,
+		}); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+
+		if err := services.ReceivePurchaseLine(tx, purchaseOrder.ID, line, expiries[line.ID], userID); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to update ingredient stock",
+				"error":   err.Error(),
+			})
+		}
+		total += line.ReceivedQuantity * line.UnitCost
+	}
+
+	now := time.Now()
+	if _, err := tx.Model(&models.PurchaseOrders{}).Where("id = ?", purchaseOrder.ID).Update(map[string]interface{}{
+		"status":      services.PurchaseOrderReceived,
+		"received_at": now,
+		"total":       total,
+	}); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Purchase order received successfully",
+		"data": map[string]interface{}{
+			"id":          purchaseOrder.ID,
+			"status":      services.PurchaseOrderReceived,
+			"received_at": now,
+			"total":       total,
+		},
+	})
+}
+
+func (p *PurchaseOrderController) Cancel(ctx http.Context) http.Response {
+	purchaseOrder, err := findPurchaseOrder(ctx)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if purchaseOrder.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Purchase order not found",
+		})
+	}
+	if purchaseOrder.Status != services.PurchaseOrderDraft && purchaseOrder.Status != services.PurchaseOrderSent {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Only draft or sent purchase orders can be cancelled",
+		})
+	}
+
+	// Receive may have booked the order into stock since it was loaded
+	result, err := facades.Orm().Query().Model(&models.PurchaseOrders{}).
+		Where("id = ? AND status IN ?", purchaseOrder.ID, []string{services.PurchaseOrderDraft, services.PurchaseOrderSent}).
+		Update("status", services.PurchaseOrderCancelled)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if result.RowsAffected == 0 {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Only draft or sent purchase orders can be cancelled",
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Purchase order cancelled successfully",
+	})
+}
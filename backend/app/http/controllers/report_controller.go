@@ -0,0 +1,377 @@
+package controllers
+
+import (
+	"sort"
+	"time"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type ReportController struct {
+}
+
+type marginRow struct {
+	ProductID     int64   `json:"product_id"`
+	Name          string  `json:"name"`
+	Quantity      int     `json:"quantity"`
+	Revenue       float64 `json:"revenue"`
+	NetRevenue    float64 `json:"net_revenue"`
+	UnitFoodCost  float64 `json:"unit_food_cost"`
+	FoodCost      float64 `json:"food_cost"`
+	GrossMargin   float64 `json:"gross_margin"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+func (row *marginRow) finish() {
+	row.GrossMargin = row.NetRevenue - row.FoodCost
+	if row.NetRevenue > 0 {
+		row.MarginPercent = row.GrossMargin / row.NetRevenue * 100
+	}
+}
+
+func sortedMarginRows(rows map[int64]*marginRow) []marginRow {
+	result := make([]marginRow, 0, len(rows))
+	for _, row := range rows {
+		row.finish()
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].GrossMargin > result[j].GrossMargin })
+
+	return result
+}
+
+// parseReportRange reads ?from= and ?to= (YYYY-MM-DD, both inclusive),
+// defaulting to the current month.
+func parseReportRange(ctx http.Context) (time.Time, time.Time, bool) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 1, 0)
+
+	if fromStr := ctx.Request().Query("from"); fromStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", fromStr, now.Location())
+		if err != nil {
+			return from, to, false
+		}
+		from = parsed
+	}
+	if toStr := ctx.Request().Query("to"); toStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", toStr, now.Location())
+		if err != nil {
+			return from, to, false
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	return from, to, true
+}
+
+// GetProductCosts - Giá vốn nguyên liệu và biên lợi nhuận lý thuyết của từng sản phẩm
+func (r *ReportController) GetProductCosts(ctx http.Context) http.Response {
+	var products []models.Product
+	if err := facades.Orm().Query().Order("name asc").Find(&products); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	foodCosts, err := services.ProductFoodCosts()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	var result []map[string]interface{}
+	for _, product := range products {
+		foodCost, hasRecipe := foodCosts[product.ID]
+		var marginPercent float64
+		if product.Price > 0 {
+			marginPercent = (product.Price - foodCost) / product.Price * 100
+		}
+		result = append(result, map[string]interface{}{
+			"product_id":     product.ID,
+			"name":           product.Name,
+			"price":          product.Price,
+			"food_cost":      foodCost,
+			"margin":         product.Price - foodCost,
+			"margin_percent": marginPercent,
+			"has_recipe":     hasRecipe,
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Product costs fetched successfully",
+		"data":    result,
+	})
+}
+
+// GetProductMargins - Báo cáo lãi gộp theo sản phẩm và theo kỳ từ các đơn hàng đã hoàn thành.
+// Order discounts are spread over the order lines in proportion to their value.
+func (r *ReportController) GetProductMargins(ctx http.Context) http.Response {
+	from, to, ok := parseReportRange(ctx)
+	if !ok {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "Invalid date format. Please use YYYY-MM-DD",
+		})
+	}
+
+	periodLayouts := map[string]string{
+		"day":   "2006-01-02",
+		"month": "2006-01",
+		"year":  "2006",
+	}
+	period := ctx.Request().Query("period", "month")
+	layout, ok := periodLayouts[period]
+	if !ok {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message":       "Invalid period",
+			"valid_periods": []string{"day", "month", "year"},
+		})
+	}
+
+	var orders []models.Orders
+	if err := facades.Orm().Query().Where("status = ? AND created_at >= ? AND created_at < ?", "completed", from, to).Find(&orders); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	foodCosts, err := services.ProductFoodCosts()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	orderIDs := make([]any, 0, len(orders))
+	ordersByID := map[int64]models.Orders{}
+	for _, order := range orders {
+		orderIDs = append(orderIDs, order.ID)
+		ordersByID[order.ID] = order
+	}
+
+	var orderItems []models.OrderItems
+	if len(orderIDs) > 0 {
+		if err := facades.Orm().Query().WhereIn("order_id", orderIDs).With("Product", withTrashedProducts).Find(&orderItems); err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	subtotals := map[int64]float64{}
+	for _, item := range orderItems {
+		subtotals[item.OrderID] += item.UnitPrice * float64(item.Quantity)
+	}
+
+	totals := map[int64]*marginRow{}
+	periods := map[string]map[int64]*marginRow{}
+	for _, item := range orderItems {
+		order := ordersByID[item.OrderID]
+		revenue := item.UnitPrice * float64(item.Quantity)
+		netRevenue := revenue
+		if subtotals[item.OrderID] > 0 {
+			netRevenue = revenue * order.Total / subtotals[item.OrderID]
+		}
+
+		key := order.CreatedAt.Format(layout)
+		if periods[key] == nil {
+			periods[key] = map[int64]*marginRow{}
+		}
+		for _, rows := range []map[int64]*marginRow{totals, periods[key]} {
+			row := rows[item.ProductID]
+			if row == nil {
+				row = &marginRow{ProductID: item.ProductID, Name: item.Product.Name, UnitFoodCost: foodCosts[item.ProductID]}
+				rows[item.ProductID] = row
+			}
+			row.Quantity += item.Quantity
+			row.Revenue += revenue
+			row.NetRevenue += netRevenue
+			row.FoodCost += foodCosts[item.ProductID] * services.OrderItemFactor(item) * float64(item.Quantity)
+		}
+	}
+
+	summary := marginRow{}
+	for _, row := range totals {
+		summary.Quantity += row.Quantity
+		summary.Revenue += row.Revenue
+		summary.NetRevenue += row.NetRevenue
+		summary.FoodCost += row.FoodCost
+	}
+	summary.finish()
+
+	periodKeys := make([]string, 0, len(periods))
+	for key := range periods {
+		periodKeys = append(periodKeys, key)
+	}
+	sort.Strings(periodKeys)
+
+	var periodRows []map[string]interface{}
+	for _, key := range periodKeys {
+		periodSummary := marginRow{}
+		for _, row := range periods[key] {
+			periodSummary.Revenue += row.Revenue
+			periodSummary.NetRevenue += row.NetRevenue
+			periodSummary.FoodCost += row.FoodCost
+		}
+		periodSummary.finish()
+		periodRows = append(periodRows, map[string]interface{}{
+			"period":         key,
+			"revenue":        periodSummary.Revenue,
+			"net_revenue":    periodSummary.NetRevenue,
+			"food_cost":      periodSummary.FoodCost,
+			"gross_margin":   periodSummary.GrossMargin,
+			"margin_percent": periodSummary.MarginPercent,
+			"products":       sortedMarginRows(periods[key]),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Product margins fetched successfully",
+		"data": map[string]interface{}{
+			"from":   from.Format("2006-01-02"),
+			"to":     to.AddDate(0, 0, -1).Format("2006-01-02"),
+			"period": period,
+			"summary": map[string]interface{}{
+				"orders":         len(orders),
+				"revenue":        summary.Revenue,
+				"net_revenue":    summary.NetRevenue,
+				"food_cost":      summary.FoodCost,
+				"gross_margin":   summary.GrossMargin,
+				"margin_percent": summary.MarginPercent,
+			},
+			"products": sortedMarginRows(totals),
+			"periods":  periodRows,
+		},
+	})
+}
+
+type priceComparisonRow struct {
+	ProductID           int64   `json:"product_id"`
+	Name                string  `json:"name"`
+	Quantity            int     `json:"quantity"`
+	Revenue             float64 `json:"revenue"`
+	AverageSalePrice    float64 `json:"average_sale_price"`
+	AverageListPrice    float64 `json:"average_list_price"`
+	CurrentPrice        float64 `json:"current_price"`
+	RevenueAtCurrent    float64 `json:"revenue_at_current_price"`
+	Difference          float64 `json:"difference"`
+	DifferencePercent   float64 `json:"difference_percent"`
+	listPriceQuantities float64
+}
+
+// GetPriceComparison - So sánh giá niêm yết lúc bán (theo lịch sử giá) với
+// giá hiện tại cho các đơn hoàn thành trong ?from= ?to=. Lịch sử giá chỉ có
+// theo sản phẩm nên chỉ tính các dòng không có kích cỡ và không thuộc combo,
+// giá bán đã trừ phần lựa chọn thêm
+func (r *ReportController) GetPriceComparison(ctx http.Context) http.Response {
+	from, to, ok := parseReportRange(ctx)
+	if !ok {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "Invalid date format. Please use YYYY-MM-DD",
+		})
+	}
+
+	var orders []models.Orders
+	if err := facades.Orm().Query().Where("status = ? AND created_at >= ? AND created_at < ?", "completed", from, to).Find(&orders); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	orderIDs := make([]any, 0, len(orders))
+	ordersByID := map[int64]models.Orders{}
+	for _, order := range orders {
+		orderIDs = append(orderIDs, order.ID)
+		ordersByID[order.ID] = order
+	}
+
+	var orderItems []models.OrderItems
+	if len(orderIDs) > 0 {
+		if err := facades.Orm().Query().WhereIn("order_id", orderIDs).Where("variant_id IS NULL AND order_combo_id IS NULL").
+			With("Product", withTrashedProducts).With("Modifiers").Find(&orderItems); err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	productIDs := []any{}
+	seen := map[int64]bool{}
+	for _, item := range orderItems {
+		if !seen[item.ProductID] {
+			seen[item.ProductID] = true
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+	histories, err := services.ProductPriceHistories(productIDs)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	rows := map[int64]*priceComparisonRow{}
+	for _, item := range orderItems {
+		row := rows[item.ProductID]
+		if row == nil {
+			row = &priceComparisonRow{ProductID: item.ProductID, Name: item.Product.Name, CurrentPrice: item.Product.Price}
+			rows[item.ProductID] = row
+		}
+		quantity := float64(item.Quantity)
+		row.Quantity += item.Quantity
+		unitPrice := item.UnitPrice
+		for _, modifier := range item.Modifiers {
+			unitPrice -= modifier.PriceDelta
+		}
+		row.Revenue += unitPrice * quantity
+		row.RevenueAtCurrent += item.Product.Price * quantity
+
+		listPrice, ok := services.PriceAt(histories[item.ProductID], ordersByID[item.OrderID].CreatedAt)
+		if !ok {
+			listPrice = item.Product.Price
+		}
+		row.listPriceQuantities += listPrice * quantity
+	}
+
+	result := make([]priceComparisonRow, 0, len(rows))
+	summary := map[string]float64{"revenue": 0, "revenue_at_current_price": 0, "difference": 0}
+	for _, row := range rows {
+		if row.Quantity > 0 {
+			row.AverageSalePrice = row.Revenue / float64(row.Quantity)
+			row.AverageListPrice = row.listPriceQuantities / float64(row.Quantity)
+		}
+		row.Difference = row.RevenueAtCurrent - row.Revenue
+		if row.Revenue > 0 {
+			row.DifferencePercent = row.Difference / row.Revenue * 100
+		}
+		summary["revenue"] += row.Revenue
+		summary["revenue_at_current_price"] += row.RevenueAtCurrent
+		summary["difference"] += row.Difference
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Difference > result[j].Difference })
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Price comparison fetched successfully",
+		"data": map[string]interface{}{
+			"from":     from.Format("2006-01-02"),
+			"to":       to.AddDate(0, 0, -1).Format("2006-01-02"),
+			"orders":   len(orders),
+			"summary":  summary,
+			"products": result,
+		},
+	})
+}
@@ -0,0 +1,341 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type ScheduleController struct {
+}
+
+type scheduleRequest struct {
+	Name    string                       `json:"name"`
+	Periods []models.AvailabilityPeriods `json:"periods"`
+}
+
+// validateScheduleRequest returns a message for the client when the request is invalid.
+func validateScheduleRequest(req *scheduleRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "name is required"
+	}
+	if len(req.Periods) == 0 {
+		return "a schedule needs at least one period"
+	}
+	for _, period := range req.Periods {
+		if err := services.ValidatePeriod(period); err != nil {
+			return err.Error()
+		}
+	}
+
+	return ""
+}
+
+// saveSchedulePeriods creates the periods of a schedule.
+func saveSchedulePeriods(tx orm.Query, schedule *models.AvailabilitySchedules, periods []models.AvailabilityPeriods) error {
+	schedule.Periods = []models.AvailabilityPeriods{}
+	for _, period := range periods {
+		period.ID = 0
+		period.ScheduleID = schedule.ID
+		if err := tx.Create(&period); err != nil {
+			return err
+		}
+		schedule.Periods = append(schedule.Periods, period)
+	}
+
+	return nil
+}
+
+func (s *ScheduleController) GetAll(ctx http.Context) http.Response {
+	schedules := []models.AvailabilitySchedules{}
+	if err := facades.Orm().Query().With("Periods", func(query orm.Query) orm.Query {
+		return query.OrderBy("weekday").OrderBy("start_time")
+	}).OrderBy("name").Find(&schedules); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	now := services.RestaurantNow()
+	result := make([]map[string]interface{}, 0, len(schedules))
+	for _, schedule := range schedules {
+		result = append(result, map[string]interface{}{
+			"id":         schedule.ID,
+			"name":       schedule.Name,
+			"periods":    schedule.Periods,
+			"open_now":   services.ScheduleOpenAt(schedule, now),
+			"created_at": schedule.CreatedAt,
+			"updated_at": schedule.UpdatedAt,
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message":       "Schedules fetched successfully",
+		"data":          result,
+		"timezone":      services.RestaurantLocation().String(),
+		"restaurant_at": now.Format("2006-01-02 15:04"),
+	})
+}
+
+// Create adds a menu schedule such as breakfast or late night.
+// Body: {"name":"Breakfast","periods":[{"weekday":1,"start_time":"06:30","end_time":"10:30"}]}
+// Weekdays run from 0 (Sunday) to 6 (Saturday); a period ending before it
+// starts runs past midnight.
+func (s *ScheduleController) Create(ctx http.Context) http.Response {
+	var req scheduleRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if message := validateScheduleRequest(&req); message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	schedule := models.AvailabilitySchedules{Name: req.Name}
+	if err := tx.Create(&schedule); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if err := saveSchedulePeriods(tx, &schedule, req.Periods); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Schedule created successfully",
+		"data":    schedule,
+	})
+}
+
+// Update renames a schedule and replaces its periods.
+func (s *ScheduleController) Update(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req scheduleRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if message := validateScheduleRequest(&req); message != "" {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": message,
+		})
+	}
+
+	var schedule models.AvailabilitySchedules
+	if err := facades.Orm().Query().Where("id = ?", id).First(&schedule); err != nil || schedule.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Schedule not found",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	schedule.Name = req.Name
+	if err := tx.Save(&schedule); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update schedule",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := tx.Model(&models.AvailabilityPeriods{}).Where("schedule_id = ?", schedule.ID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update schedule",
+			"error":   err.Error(),
+		})
+	}
+	if err := saveSchedulePeriods(tx, &schedule, req.Periods); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update schedule",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Schedule updated successfully",
+		"data":    schedule,
+	})
+}
+
+// Delete removes a schedule. Products and categories that used it become
+// available all day.
+func (s *ScheduleController) Delete(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	for _, model := range []any{&models.Product{}, &models.Category{}} {
+		if _, err := tx.Model(model).Where("availability_schedule_id = ?", id).Update("availability_schedule_id", nil); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to delete schedule",
+				"error":   err.Error(),
+			})
+		}
+	}
+	if _, err := tx.Model(&models.AvailabilityPeriods{}).Where("schedule_id = ?", id).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete schedule",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := tx.Model(&models.AvailabilitySchedules{}).Where("id = ?", id).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete schedule",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Schedule deleted successfully",
+	})
+}
+
+// assignSchedule sets or clears the schedule of a product or category.
+// Body: {"schedule_id":2}, or {"schedule_id":null} to sell all day.
+func assignSchedule(ctx http.Context, model any, notFound string) http.Response {
+	type AssignScheduleRequest struct {
+		ScheduleID *int64 `json:"schedule_id"`
+	}
+
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req AssignScheduleRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if req.ScheduleID != nil && *req.ScheduleID == 0 {
+		req.ScheduleID = nil
+	}
+	if req.ScheduleID != nil {
+		count, err := facades.Orm().Query().Model(&models.AvailabilitySchedules{}).Where("id = ?", *req.ScheduleID).Count()
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		if count == 0 {
+			return ctx.Response().Json(404, map[string]interface{}{
+				"message": "Schedule not found",
+			})
+		}
+	}
+
+	result, err := facades.Orm().Query().Model(model).Where("id = ?", id).Update("availability_schedule_id", req.ScheduleID)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if result.RowsAffected == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": notFound,
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Schedule assigned successfully",
+		"data": map[string]interface{}{
+			"id":                       id,
+			"availability_schedule_id": req.ScheduleID,
+		},
+	})
+}
+
+// AssignProduct sets the schedule of a product, overriding its category's.
+func (s *ScheduleController) AssignProduct(ctx http.Context) http.Response {
+	return assignSchedule(ctx, &models.Product{}, "Product not found")
+}
+
+// AssignCategory sets the schedule shared by the products of a category.
+func (s *ScheduleController) AssignCategory(ctx http.Context) http.Response {
+	return assignSchedule(ctx, &models.Category{}, "Category not found")
+}
@@ -0,0 +1,341 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/http/utils"
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type StocktakeController struct {
+}
+
+func findStocktake(ctx http.Context) (models.Stocktakes, error) {
+	var stocktake models.Stocktakes
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return stocktake, nil
+	}
+	err = facades.Orm().Query().Where("id = ?", id).With("Lines.Ingredient").First(&stocktake)
+
+	return stocktake, err
+}
+
+// Create opens a new stocktake. Only one stocktake can be open at a time so
+// counts are never split across sessions.
+func (s *StocktakeController) Create(ctx http.Context) http.Response {
+	userID, err := utils.GetUserIDFromToken(ctx)
+	if err != nil || userID == 0 {
+		return ctx.Response().Json(401, map[string]interface{}{
+			"message": "Unauthorized - user_id not found",
+		})
+	}
+
+	openCount, err := facades.Orm().Query().Model(&models.Stocktakes{}).Where("status = ?", services.StocktakeOpen).Count()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if openCount > 0 {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Another stocktake is still open",
+		})
+	}
+
+	stocktake := models.Stocktakes{
+		Status:   services.StocktakeOpen,
+		Note:     ctx.Request().Input("note"),
+		OpenedBy: userID,
+		Lines:    []models.StocktakeLines{},
+	}
+	if err := facades.Orm().Query().Create(&stocktake); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Stocktake opened successfully",
+		"data":    stocktake,
+	})
+}
+
+func (s *StocktakeController) GetAll(ctx http.Context) http.Response {
+	query := facades.Orm().Query().Model(&models.Stocktakes{})
+	if status := ctx.Request().Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	stocktakes := []models.Stocktakes{}
+	if err := query.Order("created_at desc").Find(&stocktakes); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Stocktakes fetched successfully",
+		"data":    stocktakes,
+	})
+}
+
+func (s *StocktakeController) GetById(ctx http.Context) http.Response {
+	stocktake, err := findStocktake(ctx)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if stocktake.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Stocktake not found",
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Stocktake fetched successfully",
+		"data":    stocktake,
+	})
+}
+
+// RecordCounts stores counted quantities for an open stocktake.
+// Body: {"counts":[{"ingredient_id":1,"counted_quantity":12.5,"unit":"kg"}]}
+func (s *StocktakeController) RecordCounts(ctx http.Context) http.Response {
+	type CountRequest struct {
+		IngredientID    int64    `json:"ingredient_id"`
+		CountedQuantity *float64 `json:"counted_quantity"`
+		Unit            string   `json:"unit"`
+	}
+	type RecordCountsRequest struct {
+		Counts []CountRequest `json:"counts"`
+	}
+
+	var req RecordCountsRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if len(req.Counts) == 0 {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "counts is required",
+		})
+	}
+	for _, count := range req.Counts {
+		if count.IngredientID <= 0 || count.CountedQuantity == nil || *count.CountedQuantity < 0 {
+			return ctx.Response().Json(422, map[string]interface{}{
+				"message": "Each count needs an ingredient_id and a counted_quantity of at least 0",
+			})
+		}
+	}
+
+	userID, err := utils.GetUserIDFromToken(ctx)
+	if err != nil || userID == 0 {
+		return ctx.Response().Json(401, map[string]interface{}{
+			"message": "Unauthorized - user_id not found",
+		})
+	}
+
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	// Lock the stocktake so counts cannot land after it has been closed
+	var stocktake models.Stocktakes
+	if err := tx.Model(&models.Stocktakes{}).LockForUpdate().Where("id = ?", id).First(&stocktake); err != nil || stocktake.ID == 0 {
+		tx.Rollback()
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Stocktake not found",
+		})
+	}
+	if stocktake.Status != services.StocktakeOpen {
+		tx.Rollback()
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Only open stocktakes can be counted",
+		})
+	}
+
+	lines := []models.StocktakeLines{}
+	for _, count := range req.Counts {
+		line, err := services.RecordStocktakeCount(tx, stocktake.ID, count.IngredientID, *count.CountedQuantity, count.Unit, userID)
+		if err != nil {
+			tx.Rollback()
+			if errors.Is(err, services.ErrIngredientNotFound) {
+				return ctx.Response().Json(404, map[string]interface{}{
+					"message":       "Ingredient not found",
+					"ingredient_id": count.IngredientID,
+				})
+			}
+			if errors.Is(err, services.ErrUnknownUnit) || errors.Is(err, services.ErrIncompatibleUnits) {
+				return ctx.Response().Json(422, map[string]interface{}{
+					"message":       err.Error(),
+					"ingredient_id": count.IngredientID,
+				})
+			}
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		lines = append(lines, line)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Counts recorded successfully",
+		"data":    lines,
+	})
+}
+
+// Close applies the counted quantities to stock and returns the variance report.
+func (s *StocktakeController) Close(ctx http.Context) http.Response {
+	userID, err := utils.GetUserIDFromToken(ctx)
+	if err != nil || userID == 0 {
+		return ctx.Response().Json(401, map[string]interface{}{
+			"message": "Unauthorized - user_id not found",
+		})
+	}
+
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	var stocktake models.Stocktakes
+	if err := tx.Model(&models.Stocktakes{}).LockForUpdate().Where("id = ?", id).First(&stocktake); err != nil || stocktake.ID == 0 {
+		tx.Rollback()
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Stocktake not found",
+		})
+	}
+	if stocktake.Status != services.StocktakeOpen {
+		tx.Rollback()
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Only open stocktakes can be closed",
+		})
+	}
+
+	if err := services.CloseStocktake(tx, stocktake.ID, userID); err != nil {
+		tx.Rollback()
+		if errors.Is(err, services.ErrInsufficientStock) {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message": "Stock changed since it was counted, please recount",
+				"error":   err.Error(),
+			})
+		}
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to apply stocktake",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	stocktake, err = findStocktake(ctx)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Stocktake closed successfully",
+		"data":    services.BuildStocktakeVariance(stocktake),
+	})
+}
+
+func (s *StocktakeController) Cancel(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	result, err := facades.Orm().Query().Model(&models.Stocktakes{}).
+		Where("id = ? AND status = ?", id, services.StocktakeOpen).
+		Update("status", services.StocktakeCancelled)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if result.RowsAffected == 0 {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Only open stocktakes can be cancelled",
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Stocktake cancelled successfully",
+	})
+}
+
+// GetVariance returns expected vs counted quantities valued at cost. For an
+// open stocktake this is a preview, nothing has been applied yet.
+func (s *StocktakeController) GetVariance(ctx http.Context) http.Response {
+	stocktake, err := findStocktake(ctx)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if stocktake.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Stocktake not found",
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Stocktake variance fetched successfully",
+		"data":    services.BuildStocktakeVariance(stocktake),
+	})
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+type SupplierController struct {
+}
+
+func (s *SupplierController) Create(ctx http.Context) http.Response {
+	validator, err := ctx.Request().Validate(map[string]string{
+		"name":         "required|string",
+		"contact_name": "string",
+		"phone":        "string",
+		"email":        "email",
+		"address":      "string",
+		"note":         "string",
+	})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if validator.Fails() {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "Validation failed",
+			"errors":  validator.Errors().All(),
+		})
+	}
+
+	supplier := models.Suppliers{
+		Name:        ctx.Request().Input("name"),
+		ContactName: ctx.Request().Input("contact_name"),
+		Phone:       ctx.Request().Input("phone"),
+		Email:       ctx.Request().Input("email"),
+		Address:     ctx.Request().Input("address"),
+		Note:        ctx.Request().Input("note"),
+		IsActive:    true,
+	}
+	if err := facades.Orm().Query().Create(&supplier); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Supplier created successfully",
+		"data":    supplier,
+	})
+}
+
+func (s *SupplierController) GetAll(ctx http.Context) http.Response {
+	query := facades.Orm().Query().Model(&models.Suppliers{})
+	if ctx.Request().Query("active") == "true" {
+		query = query.Where("is_active = ?", true)
+	}
+
+	suppliers := []models.Suppliers{}
+	if err := query.Order("name asc").Find(&suppliers); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Suppliers fetched successfully",
+		"data":    suppliers,
+	})
+}
+
+func (s *SupplierController) GetById(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var supplier models.Suppliers
+	if err := facades.Orm().Query().Where("id = ?", id).First(&supplier); err != nil || supplier.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Supplier not found",
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Supplier fetched successfully",
+		"data":    supplier,
+	})
+}
+
+func (s *SupplierController) Update(ctx http.Context) http.Response {
+	validator, err := ctx.Request().Validate(map[string]string{
+		"name":         "required|string",
+		"contact_name": "string",
+		"phone":        "string",
+		"email":        "email",
+		"address":      "string",
+		"note":         "string",
+		"is_active":    "required|boolean",
+	})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if validator.Fails() {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message": "Validation failed",
+			"errors":  validator.Errors().All(),
+		})
+	}
+
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	updateData := map[string]interface{}{
+		"name":         ctx.Request().Input("name"),
+		"contact_name": ctx.Request().Input("contact_name"),
+		"phone":        ctx.Request().Input("phone"),
+		"email":        ctx.Request().Input("email"),
+		"address":      ctx.Request().Input("address"),
+		"note":         ctx.Request().Input("note"),
+		"is_active":    ctx.Request().Input("is_active") == "true",
+	}
+	if _, err := facades.Orm().Query().Model(&models.Suppliers{}).Where("id = ?", id).Update(updateData); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update supplier",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Supplier updated successfully",
+		"data":    updateData,
+	})
+}
+
+func (s *SupplierController) Delete(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	// Suppliers with purchase history are deactivated instead of deleted
+	orderCount, err := facades.Orm().Query().Model(&models.PurchaseOrders{}).Where("supplier_id = ?", id).Count()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if orderCount > 0 {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Supplier has purchase orders, deactivate it instead",
+		})
+	}
+
+	if _, err := facades.Orm().Query().Model(&models.Suppliers{}).Where("id = ?", id).Delete(); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete supplier",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Supplier deleted successfully",
+	})
+}
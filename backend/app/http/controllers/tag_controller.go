@@ -0,0 +1,434 @@
+package controllers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type TagController struct {
+}
+
+type tagRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	Type string `json:"type"`
+}
+
+type tagIDsRequest struct {
+	TagIDs []int64 `json:"tag_ids"`
+}
+
+var tagSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// validateTagRequest returns a message for the client when the request is invalid.
+func validateTagRequest(req *tagRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Slug = strings.ToLower(strings.TrimSpace(req.Slug))
+	if req.Slug == "" {
+		req.Slug = strings.Join(strings.Fields(strings.ToLower(req.Name)), "-")
+	}
+	if req.Name == "" {
+		return "name is required"
+	}
+	if !tagSlugPattern.MatchString(req.Slug) {
+		return "slug may only contain lower case letters, digits and dashes"
+	}
+	if req.Type != services.TagTypeDietary && req.Type != services.TagTypeAllergen {
+		return "type must be dietary or allergen"
+	}
+
+	return ""
+}
+
+// loadTags checks that every id exists, optionally restricted to one tag type.
+func loadTags(tagIDs []int64, tagType string) ([]models.Tags, string, error) {
+	seen := map[int64]bool{}
+	ids := make([]any, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		if seen[tagID] {
+			return nil, "Each tag can only be attached once", nil
+		}
+		seen[tagID] = true
+		ids = append(ids, tagID)
+	}
+
+	tags := []models.Tags{}
+	if len(ids) == 0 {
+		return tags, "", nil
+	}
+	query := facades.Orm().Query().WhereIn("id", ids)
+	if tagType != "" {
+		query = query.Where("type = ?", tagType)
+	}
+	if err := query.Find(&tags); err != nil {
+		return nil, "", err
+	}
+	if len(tags) != len(ids) {
+		if tagType != "" {
+			return nil, "Tag not found or not of type " + tagType, nil
+		}
+		return nil, "Tag not found", nil
+	}
+
+	return tags, "", nil
+}
+
+// GetAll lists tags, optionally filtered by ?type=dietary|allergen.
+func (t *TagController) GetAll(ctx http.Context) http.Response {
+	query := facades.Orm().Query()
+	if tagType := ctx.Request().Query("type"); tagType != "" {
+		query = query.Where("type = ?", tagType)
+	}
+
+	tags := []models.Tags{}
+	if err := query.OrderBy("type").OrderBy("name").Find(&tags); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Get tags successfully",
+		"data":    tags,
+	})
+}
+
+func (t *TagController) Create(ctx http.Context) http.Response {
+	var req tagRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if message := validateTagRequest(&req); message != "" {
+		return ctx.Response().Json(422, http.Json{
+			"message": message,
+		})
+	}
+
+	exists, err := facades.Orm().Query().Model(&models.Tags{}).Where("slug = ?", req.Slug).Count()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if exists > 0 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "A tag with this slug already exists",
+		})
+	}
+
+	tag := models.Tags{Name: req.Name, Slug: req.Slug, Type: req.Type}
+	if err := facades.Orm().Query().Create(&tag); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to create tag",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Tag created successfully",
+		"data":    tag,
+	})
+}
+
+func (t *TagController) Update(ctx http.Context) http.Response {
+	tagID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req tagRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if message := validateTagRequest(&req); message != "" {
+		return ctx.Response().Json(422, http.Json{
+			"message": message,
+		})
+	}
+
+	var tag models.Tags
+	if err := facades.Orm().Query().Where("id = ?", tagID).First(&tag); err != nil || tag.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Tag not found",
+		})
+	}
+
+	exists, err := facades.Orm().Query().Model(&models.Tags{}).Where("slug = ? AND id <> ?", req.Slug, tagID).Count()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if exists > 0 {
+		return ctx.Response().Json(422, http.Json{
+			"message": "A tag with this slug already exists",
+		})
+	}
+
+	// Ingredients only carry allergens, so a tag used on ingredients keeps its type
+	if tag.Type == services.TagTypeAllergen && req.Type != services.TagTypeAllergen {
+		used, err := facades.Orm().Query().Model(&models.IngredientTags{}).Where("tag_id = ?", tagID).Count()
+		if err != nil {
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Internal server error",
+				"error":   err.Error(),
+			})
+		}
+		if used > 0 {
+			return ctx.Response().Json(400, map[string]interface{}{
+				"message": "Tag is set on ingredients and must stay an allergen",
+			})
+		}
+	}
+
+	tag.Name = req.Name
+	tag.Slug = req.Slug
+	tag.Type = req.Type
+	if err := facades.Orm().Query().Save(&tag); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update tag",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Tag updated successfully",
+		"data":    tag,
+	})
+}
+
+// Delete removes a tag from every product and ingredient before deleting it.
+func (t *TagController) Delete(ctx http.Context) http.Response {
+	tagID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	for _, model := range []any{&models.ProductTags{}, &models.IngredientTags{}} {
+		if _, err := tx.Model(model).Where("tag_id = ?", tagID).Delete(); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to delete tag",
+				"error":   err.Error(),
+			})
+		}
+	}
+	result, err := tx.Model(&models.Tags{}).Where("id = ?", tagID).Delete()
+	if err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to delete tag",
+			"error":   err.Error(),
+		})
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Tag not found",
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Tag deleted successfully",
+	})
+}
+
+// SetProductTags replaces the tags set directly on a product. Allergens
+// coming from recipe ingredients are added on top when the product is read.
+// Body: {"tag_ids":[1,4]}
+func (t *TagController) SetProductTags(ctx http.Context) http.Response {
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req tagIDsRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	var productModel models.Product
+	if err := facades.Orm().Query().Where("id = ?", productID).First(&productModel); err != nil || productModel.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Product not found",
+		})
+	}
+
+	tags, message, err := loadTags(req.TagIDs, "")
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if message != "" {
+		return ctx.Response().Json(422, http.Json{
+			"message": message,
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.ProductTags{}).Where("product_id = ?", productID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update product tags",
+			"error":   err.Error(),
+		})
+	}
+	for _, tag := range tags {
+		if err := tx.Create(&models.ProductTags{ProductID: productID, TagID: tag.ID}); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to update product tags",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	productTags, err := services.ProductTags([]int64{productID})
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Product tags updated successfully",
+		"data":    productTags[productID],
+	})
+}
+
+// SetIngredientAllergens replaces the allergens an ingredient carries. Every
+// product using the ingredient in its recipe is tagged with them.
+// Body: {"tag_ids":[4,6]}
+func (t *TagController) SetIngredientAllergens(ctx http.Context) http.Response {
+	ingredientID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req tagIDsRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	var ingredient models.Ingredients
+	if err := facades.Orm().Query().Where("id = ?", ingredientID).First(&ingredient); err != nil || ingredient.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Ingredient not found",
+		})
+	}
+
+	tags, message, err := loadTags(req.TagIDs, services.TagTypeAllergen)
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+	if message != "" {
+		return ctx.Response().Json(422, http.Json{
+			"message": message,
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.IngredientTags{}).Where("ingredient_id = ?", ingredientID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update ingredient allergens",
+			"error":   err.Error(),
+		})
+	}
+	for _, tag := range tags {
+		if err := tx.Create(&models.IngredientTags{IngredientID: ingredientID, TagID: tag.ID}); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to update ingredient allergens",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Ingredient allergens updated successfully",
+		"data":    tags,
+	})
+}
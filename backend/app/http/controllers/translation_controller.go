@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type TranslationController struct {
+}
+
+type translationItem struct {
+	Locale      string `json:"locale"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type translationsRequest struct {
+	Translations []translationItem `json:"translations"`
+}
+
+// validateTranslations returns a message for the client when the request is invalid.
+func validateTranslations(req *translationsRequest) string {
+	seen := map[string]bool{}
+	for i := range req.Translations {
+		item := &req.Translations[i]
+		item.Locale = strings.ToLower(strings.TrimSpace(item.Locale))
+		item.Name = strings.TrimSpace(item.Name)
+		item.Description = strings.TrimSpace(item.Description)
+		if !services.IsSupportedLocale(item.Locale) {
+			return "Unsupported locale " + item.Locale + ", use one of " + strings.Join(services.SupportedLocales(), ", ")
+		}
+		if seen[item.Locale] {
+			return "Each locale can only be given once"
+		}
+		seen[item.Locale] = true
+		if item.Name == "" {
+			return "name is required for locale " + item.Locale
+		}
+	}
+
+	return ""
+}
+
+// GetProductTranslations lists the stored translations of a product.
+func (t *TranslationController) GetProductTranslations(ctx http.Context) http.Response {
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	translations := []models.ProductTranslations{}
+	if err := facades.Orm().Query().Where("product_id = ?", productID).OrderBy("locale").Find(&translations); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Get product translations successfully",
+		"data":    translations,
+		"locales": services.SupportedLocales(),
+	})
+}
+
+// SetProductTranslations replaces the translations of a product. Locales
+// left out of the body are removed.
+// Body: {"translations":[{"locale":"en","name":"Beef pho","description":"..."}]}
+func (t *TranslationController) SetProductTranslations(ctx http.Context) http.Response {
+	productID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req translationsRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if message := validateTranslations(&req); message != "" {
+		return ctx.Response().Json(422, http.Json{
+			"message": message,
+		})
+	}
+
+	var productModel models.Product
+	if err := facades.Orm().Query().Where("id = ?", productID).First(&productModel); err != nil || productModel.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Product not found",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.ProductTranslations{}).Where("product_id = ?", productID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update product translations",
+			"error":   err.Error(),
+		})
+	}
+	translations := []models.ProductTranslations{}
+	for _, item := range req.Translations {
+		translation := models.ProductTranslations{
+			ProductID:   productID,
+			Locale:      item.Locale,
+			Name:        item.Name,
+			Description: item.Description,
+		}
+		if err := tx.Create(&translation); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to update product translations",
+				"error":   err.Error(),
+			})
+		}
+		translations = append(translations, translation)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Product translations updated successfully",
+		"data":    translations,
+	})
+}
+
+// GetCategoryTranslations lists the stored translations of a category.
+func (t *TranslationController) GetCategoryTranslations(ctx http.Context) http.Response {
+	categoryID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	translations := []models.CategoryTranslations{}
+	if err := facades.Orm().Query().Where("category_id = ?", categoryID).OrderBy("locale").Find(&translations); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Get category translations successfully",
+		"data":    translations,
+		"locales": services.SupportedLocales(),
+	})
+}
+
+// SetCategoryTranslations replaces the translations of a category. Locales
+// left out of the body are removed.
+// Body: {"translations":[{"locale":"en","name":"Noodles"}]}
+func (t *TranslationController) SetCategoryTranslations(ctx http.Context) http.Response {
+	categoryID, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var req translationsRequest
+	if err := ctx.Request().Bind(&req); err != nil {
+		return ctx.Response().Json(400, map[string]interface{}{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if message := validateTranslations(&req); message != "" {
+		return ctx.Response().Json(422, http.Json{
+			"message": message,
+		})
+	}
+
+	var categoryModel models.Category
+	if err := facades.Orm().Query().Where("id = ?", categoryID).First(&categoryModel); err != nil || categoryModel.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Category not found",
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	if _, err := tx.Model(&models.CategoryTranslations{}).Where("category_id = ?", categoryID).Delete(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update category translations",
+			"error":   err.Error(),
+		})
+	}
+	translations := []models.CategoryTranslations{}
+	for _, item := range req.Translations {
+		translation := models.CategoryTranslations{
+			CategoryID: categoryID,
+			Locale:     item.Locale,
+			Name:       item.Name,
+		}
+		if err := tx.Create(&translation); err != nil {
+			tx.Rollback()
+			return ctx.Response().Json(500, map[string]interface{}{
+				"message": "Failed to update category translations",
+				"error":   err.Error(),
+			})
+		}
+		translations = append(translations, translation)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Internal server error",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Category translations updated successfully",
+		"data":    translations,
+	})
+}
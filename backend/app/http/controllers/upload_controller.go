@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+	"goravel/app/services"
+)
+
+type UploadController struct {
+}
+
+// uploadFolders are the folders images can be uploaded into, one per kind of record.
+var uploadFolders = map[string]string{
+	"product": "products",
+	"voucher": "vouchers",
+	"combo":   "combos",
+}
+
+// storeUploadedImage stores the multipart "file" field. The response is
+// non-nil when the upload was rejected.
+func storeUploadedImage(ctx http.Context, folder string) (services.UploadedImage, http.Response) {
+	file, err := ctx.Request().File("file")
+	if err != nil {
+		return services.UploadedImage{}, ctx.Response().Json(422, map[string]interface{}{
+			"message": "file is required",
+		})
+	}
+
+	uploaded, err := services.StoreImage(file, folder)
+	if err != nil {
+		if errors.Is(err, services.ErrImageTooLarge) {
+			return uploaded, ctx.Response().Json(422, map[string]interface{}{
+				"message":  err.Error(),
+				"max_size": services.MaxUploadSize(),
+			})
+		}
+		if errors.Is(err, services.ErrUnsupportedImage) || errors.Is(err, services.ErrImageUnreadable) {
+			return uploaded, ctx.Response().Json(422, map[string]interface{}{
+				"message": err.Error(),
+			})
+		}
+		return uploaded, ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to store image",
+			"error":   err.Error(),
+		})
+	}
+
+	return uploaded, nil
+}
+
+// UploadImage stores an image before the record it belongs to is saved, the
+// returned URL is then sent as the thumbnail or image field.
+// Multipart fields: file, type (product, voucher or combo)
+func (u *UploadController) UploadImage(ctx http.Context) http.Response {
+	folder, ok := uploadFolders[ctx.Request().Input("type", "product")]
+	if !ok {
+		return ctx.Response().Json(422, map[string]interface{}{
+			"message":     "Invalid type",
+			"valid_types": []string{"product", "voucher", "combo"},
+		})
+	}
+
+	uploaded, response := storeUploadedImage(ctx, folder)
+	if response != nil {
+		return response
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Image uploaded successfully",
+		"data":    uploaded,
+	})
+}
+
+// UploadProductThumbnail uploads an image and sets it as the product thumbnail.
+func (u *UploadController) UploadProductThumbnail(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var productModel models.Product
+	if err := facades.Orm().Query().Where("id = ?", id).First(&productModel); err != nil || productModel.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Product not found",
+		})
+	}
+
+	uploaded, response := storeUploadedImage(ctx, uploadFolders["product"])
+	if response != nil {
+		return response
+	}
+
+	if _, err := facades.Orm().Query().Model(&models.Product{}).Where("id = ?", id).Update("thumbnail", uploaded.ThumbnailURL()); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update Product",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Product thumbnail uploaded successfully",
+		"data":    uploaded,
+	})
+}
+
+// UploadVoucherImage uploads an image and sets it as the voucher image.
+func (u *UploadController) UploadVoucherImage(ctx http.Context) http.Response {
+	id, err := strconv.ParseInt(ctx.Request().Route("id"), 10, 64)
+	if err != nil {
+		return ctx.Response().Json(422, http.Json{
+			"message": "Invalid id",
+		})
+	}
+
+	var voucher models.Vouchers
+	if err := facades.Orm().Query().Where("id = ?", id).First(&voucher); err != nil || voucher.ID == 0 {
+		return ctx.Response().Json(404, map[string]interface{}{
+			"message": "Voucher not found",
+		})
+	}
+
+	uploaded, response := storeUploadedImage(ctx, uploadFolders["voucher"])
+	if response != nil {
+		return response
+	}
+
+	if _, err := facades.Orm().Query().Model(&models.Vouchers{}).Where("id = ?", id).Update("image", uploaded.ThumbnailURL()); err != nil {
+		return ctx.Response().Json(500, map[string]interface{}{
+			"message": "Failed to update voucher",
+			"error":   err.Error(),
+		})
+	}
+
+	return ctx.Response().Json(200, map[string]interface{}{
+		"message": "Voucher image uploaded successfully",
+		"data":    uploaded,
+	})
+}
@@ -119,3 +119,16 @@ func Auth() http.Middleware {
 		ctx.Request().Next()
 	}
 }
+
+// OptionalAuth is Auth for routes guests may use as well: requests without an
+// Authorization header go through, a header that is sent must be valid.
+func OptionalAuth() http.Middleware {
+	auth := Auth()
+	return func(ctx http.Context) {
+		if ctx.Request().Header("Authorization") == "" {
+			ctx.Request().Next()
+			return
+		}
+		auth(ctx)
+	}
+}
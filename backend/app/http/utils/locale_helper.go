@@ -0,0 +1,13 @@
+package utils
+
+import (
+	"github.com/goravel/framework/contracts/http"
+
+	"goravel/app/services"
+)
+
+// GetLocale returns the content locale of the request, from ?lang= or the
+// Accept-Language header, falling back to app.fallback_locale.
+func GetLocale(ctx http.Context) string {
+	return services.ResolveLocale(ctx.Request().Query("lang"), ctx.Request().Header("Accept-Language"))
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type AdminNotifications struct {
+	ID           int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type         string     `gorm:"type:varchar(30);not null" json:"type"`
+	Title        string     `gorm:"type:varchar(255);not null" json:"title"`
+	Message      string     `gorm:"type:text" json:"message"`
+	IngredientID *int64     `gorm:"index" json:"ingredient_id"`
+	ReadAt       *time.Time `gorm:"type:timestamp" json:"read_at"`
+	ResolvedAt   *time.Time `gorm:"type:timestamp" json:"resolved_at"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (AdminNotifications) TableName() string {
+	return "admin_notifications"
+}
+
+func (AdminNotifications) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "type", Label: "Type", DataType: "string", IsSystem: true},
+		{Name: "title", Label: "Title", DataType: "string", IsSystem: false},
+		{Name: "message", Label: "Message", DataType: "text", IsSystem: false},
+		{Name: "ingredient_id", Label: "Ingredient ID", DataType: "integer", IsSystem: true},
+		{Name: "read_at", Label: "Read At", DataType: "datetime", IsSystem: true},
+		{Name: "resolved_at", Label: "Resolved At", DataType: "datetime", IsSystem: true},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+	}
+}
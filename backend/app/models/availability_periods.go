@@ -0,0 +1,26 @@
+package models
+
+// AvailabilityPeriods is one weekly period of a schedule. Weekday follows
+// time.Weekday (0 is Sunday) and times are "HH:MM" in the restaurant
+// timezone. A period ending before it starts runs past midnight.
+type AvailabilityPeriods struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScheduleID int64  `gorm:"not null;index" json:"schedule_id"`
+	Weekday    int    `gorm:"not null" json:"weekday"`
+	StartTime  string `gorm:"type:varchar(5);not null" json:"start_time"`
+	EndTime    string `gorm:"type:varchar(5);not null" json:"end_time"`
+}
+
+func (AvailabilityPeriods) TableName() string {
+	return "availability_periods"
+}
+
+func (AvailabilityPeriods) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "schedule_id", Label: "Schedule ID", DataType: "integer", IsSystem: true},
+		{Name: "weekday", Label: "Weekday", DataType: "integer", IsSystem: false},
+		{Name: "start_time", Label: "Start Time", DataType: "string", IsSystem: false},
+		{Name: "end_time", Label: "End Time", DataType: "string", IsSystem: false},
+	}
+}
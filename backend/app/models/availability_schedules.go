@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// AvailabilitySchedules is a named set of weekly time periods, such as a
+// breakfast or late-night menu, attached to products and categories.
+type AvailabilitySchedules struct {
+	ID        int64                 `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string                `gorm:"type:varchar(100);not null" json:"name"`
+	Periods   []AvailabilityPeriods `gorm:"foreignKey:ScheduleID" json:"periods"`
+	CreatedAt time.Time             `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time             `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (AvailabilitySchedules) TableName() string {
+	return "availability_schedules"
+}
+
+func (AvailabilitySchedules) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
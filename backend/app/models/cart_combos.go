@@ -0,0 +1,26 @@
+package models
+
+// CartCombos is a combo in a cart. Its component products are cart items
+// pointing back at it through cart_combo_id.
+type CartCombos struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CartID    int64      `gorm:"not null;index" json:"cart_id"`
+	ComboID   int64      `gorm:"not null" json:"combo_id"`
+	Combo     Combos     `gorm:"foreignKey:ComboID" json:"combo"`
+	Quantity  int        `gorm:"not null" json:"quantity"`
+	UnitPrice float64    `gorm:"-" json:"unit_price"`
+	Items     []CartItem `gorm:"foreignKey:CartComboID" json:"items"`
+}
+
+func (CartCombos) TableName() string {
+	return "cart_combos"
+}
+
+func (CartCombos) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "cart_id", Label: "Cart ID", DataType: "integer", IsSystem: false},
+		{Name: "combo_id", Label: "Combo ID", DataType: "integer", IsSystem: false},
+		{Name: "quantity", Label: "Quantity", DataType: "integer", IsSystem: false},
+	}
+}
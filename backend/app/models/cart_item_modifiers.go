@@ -0,0 +1,20 @@
+package models
+
+type CartItemModifiers struct {
+	ID               int64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	CartItemID       int64           `gorm:"not null;index" json:"cart_item_id"`
+	ModifierOptionID int64           `gorm:"not null" json:"modifier_option_id"`
+	Option           ModifierOptions `gorm:"foreignKey:ModifierOptionID" json:"option"`
+}
+
+func (CartItemModifiers) TableName() string {
+	return "cart_item_modifiers"
+}
+
+func (CartItemModifiers) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "cart_item_id", Label: "Cart Item ID", DataType: "integer", IsSystem: true},
+		{Name: "modifier_option_id", Label: "Modifier Option ID", DataType: "integer", IsSystem: false},
+	}
+}
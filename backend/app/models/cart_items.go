@@ -6,7 +6,13 @@ type CartItem struct {
 	Cart Carts `gorm:"foreignKey:CartID" json:"cart"`
 	ProductID int64 `gorm:"not null" json:"product_id"`
 	Product Product `gorm:"foreignKey:ProductID" json:"product"`
+	VariantID *int64 `json:"variant_id"`
+	Variant *ProductVariants `gorm:"foreignKey:VariantID" json:"variant"`
 	Quantity int `gorm:"not null" json:"quantity"`
+	Modifiers []CartItemModifiers `gorm:"foreignKey:CartItemID" json:"modifiers"`
+	CartComboID *int64 `json:"cart_combo_id"`
+	ComboSlotOptionID *int64 `json:"combo_slot_option_id"`
+	ComboSlotOption *ComboSlotOptions `gorm:"foreignKey:ComboSlotOptionID" json:"combo_slot_option,omitempty"`
 }
 
 func (CartItem) TableName() string {
@@ -18,6 +24,8 @@ func (CartItem) GetFields() []Field {
 		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
 		{Name: "cart_id", Label: "Cart ID", DataType: "integer", IsSystem: false},
 		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: false},
+		{Name: "variant_id", Label: "Variant ID", DataType: "integer", IsSystem: false},
 		{Name: "quantity", Label: "Quantity", DataType: "integer", IsSystem: false},
+		{Name: "cart_combo_id", Label: "Cart Combo ID", DataType: "integer", IsSystem: true},
 	}
 }
\ No newline at end of file
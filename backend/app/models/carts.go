@@ -4,7 +4,7 @@ import "time"
 
 type Carts struct {
 	ID int64 `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID int64 `gorm:"not null" json:"user_id"`
+	UserID *int64 `json:"user_id"`
 	User User `gorm:"foreignKey:UserID" json:"user"`
 	Status string `gorm:"type:varchar(50);default:'active'" json:"status"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
@@ -3,6 +3,12 @@ package models
 type Category struct {
 	ID   int64  `gorm:"primaryKey;autoIncrement" json:"id"`
 	Name string `gorm:"not null" json:"name"`
+	// ParentID is nil for top level categories
+	ParentID               *int64 `gorm:"index" json:"parent_id"`
+	SortOrder              int    `gorm:"not null;default:0" json:"sort_order"`
+	Status                 bool   `gorm:"not null" json:"status"`
+	Icon                   string `gorm:"not null;default:''" json:"icon"`
+	AvailabilityScheduleID *int64 `json:"availability_schedule_id"`
 }
 
 func (Category) TableName() string {
@@ -13,5 +19,10 @@ func (Category) GetFields() []Field {
 	return []Field{
 		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
 		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "parent_id", Label: "Parent ID", DataType: "integer", IsSystem: false},
+		{Name: "sort_order", Label: "Sort Order", DataType: "integer", IsSystem: false},
+		{Name: "status", Label: "Status", DataType: "boolean", IsSystem: false},
+		{Name: "icon", Label: "Icon", DataType: "string", IsSystem: false},
+		{Name: "availability_schedule_id", Label: "Availability Schedule ID", DataType: "integer", IsSystem: false},
 	}
 }
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// CategoryTranslations holds the name of a category in one locale.
+type CategoryTranslations struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	CategoryID int64     `gorm:"not null;index" json:"category_id"`
+	Locale     string    `gorm:"type:varchar(10);not null" json:"locale"`
+	Name       string    `gorm:"not null" json:"name"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (CategoryTranslations) TableName() string {
+	return "category_translations"
+}
+
+func (CategoryTranslations) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "category_id", Label: "Category ID", DataType: "integer", IsSystem: false},
+		{Name: "locale", Label: "Locale", DataType: "string", IsSystem: false},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+type ComboSlotOptions struct {
+	ID         int64            `gorm:"primaryKey;autoIncrement" json:"id"`
+	SlotID     int64            `gorm:"not null;index" json:"slot_id"`
+	Slot       *ComboSlots      `gorm:"foreignKey:SlotID" json:"slot,omitempty"`
+	ProductID  int64            `gorm:"not null" json:"product_id"`
+	Product    *Product         `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	VariantID  *int64           `json:"variant_id"`
+	Variant    *ProductVariants `gorm:"foreignKey:VariantID" json:"variant,omitempty"`
+	ExtraPrice float64          `gorm:"not null;default:0" json:"extra_price"`
+}
+
+func (ComboSlotOptions) TableName() string {
+	return "combo_slot_options"
+}
+
+func (ComboSlotOptions) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "slot_id", Label: "Slot ID", DataType: "integer", IsSystem: true},
+		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: false},
+		{Name: "variant_id", Label: "Variant ID", DataType: "integer", IsSystem: false},
+		{Name: "extra_price", Label: "Extra Price", DataType: "decimal", IsSystem: false},
+	}
+}
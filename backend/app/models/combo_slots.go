@@ -0,0 +1,24 @@
+package models
+
+type ComboSlots struct {
+	ID        int64              `gorm:"primaryKey;autoIncrement" json:"id"`
+	ComboID   int64              `gorm:"not null;index" json:"combo_id"`
+	Name      string             `gorm:"type:varchar(100);not null" json:"name"`
+	Quantity  int                `gorm:"not null;default:1" json:"quantity"`
+	SortOrder int                `gorm:"not null;default:0" json:"sort_order"`
+	Options   []ComboSlotOptions `gorm:"foreignKey:SlotID" json:"options"`
+}
+
+func (ComboSlots) TableName() string {
+	return "combo_slots"
+}
+
+func (ComboSlots) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "combo_id", Label: "Combo ID", DataType: "integer", IsSystem: true},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "quantity", Label: "Quantity", DataType: "integer", IsSystem: false},
+		{Name: "sort_order", Label: "Sort Order", DataType: "integer", IsSystem: false},
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Combos is a bundle of products sold at one price. Each slot is filled with
+// one of its options, a slot with a single option is a fixed item.
+type Combos struct {
+	ID          int64        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string       `gorm:"type:varchar(150);not null" json:"name"`
+	Description string       `gorm:"type:text" json:"description"`
+	Thumbnail   string       `gorm:"type:varchar(255)" json:"thumbnail"`
+	Price       float64      `gorm:"not null" json:"price"`
+	Status      bool         `gorm:"not null" json:"status"`
+	Slots       []ComboSlots `gorm:"foreignKey:ComboID" json:"slots"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Combos) TableName() string {
+	return "combos"
+}
+
+func (Combos) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "description", Label: "Description", DataType: "string", IsSystem: false},
+		{Name: "thumbnail", Label: "Thumbnail", DataType: "string", IsSystem: false},
+		{Name: "price", Label: "Price", DataType: "decimal", IsSystem: false},
+		{Name: "status", Label: "Status", DataType: "boolean", IsSystem: false},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// IngredientBatches is one delivery of an ingredient. The ingredient's
+// Quantity is always the sum of Remaining over its batches.
+type IngredientBatches struct {
+	ID              int64       `gorm:"primaryKey;autoIncrement" json:"id"`
+	IngredientID    int64       `gorm:"not null;index" json:"ingredient_id"`
+	Ingredient      Ingredients `gorm:"foreignKey:IngredientID" json:"ingredient"`
+	Quantity        float64     `gorm:"not null" json:"quantity"`
+	Remaining       float64     `gorm:"not null" json:"remaining"`
+	UnitCost        float64     `gorm:"not null;default:0" json:"unit_cost"`
+	ReceivedAt      time.Time   `gorm:"type:timestamp;not null" json:"received_at"`
+	ExpiresAt       *time.Time  `gorm:"type:timestamp" json:"expires_at"`
+	PurchaseOrderID *int64      `json:"purchase_order_id"`
+	CreatedAt       time.Time   `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (IngredientBatches) TableName() string {
+	return "ingredient_batches"
+}
+
+func (IngredientBatches) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "ingredient_id", Label: "Ingredient ID", DataType: "integer", IsSystem: false},
+		{Name: "quantity", Label: "Quantity", DataType: "decimal", IsSystem: false},
+		{Name: "remaining", Label: "Remaining", DataType: "decimal", IsSystem: true},
+		{Name: "unit_cost", Label: "Unit Cost", DataType: "decimal", IsSystem: false},
+		{Name: "received_at", Label: "Received At", DataType: "datetime", IsSystem: false},
+		{Name: "expires_at", Label: "Expires At", DataType: "datetime", IsSystem: false},
+		{Name: "purchase_order_id", Label: "Purchase Order ID", DataType: "integer", IsSystem: true},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+	}
+}
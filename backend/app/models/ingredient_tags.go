@@ -0,0 +1,22 @@
+package models
+
+// IngredientTags marks an ingredient as carrying an allergen. Products using
+// the ingredient in their recipe inherit the tag.
+type IngredientTags struct {
+	ID           int64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	IngredientID int64 `gorm:"not null;index" json:"ingredient_id"`
+	TagID        int64 `gorm:"not null;index" json:"tag_id"`
+	Tag          Tags  `gorm:"foreignKey:TagID" json:"tag"`
+}
+
+func (IngredientTags) TableName() string {
+	return "ingredient_tags"
+}
+
+func (IngredientTags) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "ingredient_id", Label: "Ingredient ID", DataType: "integer", IsSystem: false},
+		{Name: "tag_id", Label: "Tag ID", DataType: "integer", IsSystem: false},
+	}
+}
@@ -6,6 +6,14 @@ type Ingredients struct {
 	Quantity    float64 `gorm:"not null" json:"quantity"`
 	Unit        string  `gorm:"not null" json:"unit"`
 	Threshold   float64 `gorm:"not null" json:"threshold"`
+	UnitCost    float64 `gorm:"not null;default:0" json:"unit_cost"`
+	// Nutrition per one Unit of the ingredient, only meaningful when HasNutrition is set
+	HasNutrition bool    `gorm:"not null;default:false" json:"has_nutrition"`
+	Kcal         float64 `gorm:"not null;default:0" json:"kcal"`
+	Protein      float64 `gorm:"not null;default:0" json:"protein"`
+	Fat          float64 `gorm:"not null;default:0" json:"fat"`
+	Carbs        float64 `gorm:"not null;default:0" json:"carbs"`
+	Sodium       float64 `gorm:"not null;default:0" json:"sodium"`
 }
 
 func (Ingredients) TableName() string {
@@ -19,5 +27,12 @@ func (Ingredients) GetFields() []Field {
 		{Name: "quantity", Label: "Quantity", DataType: "decimal", IsSystem: false},
 		{Name: "unit", Label: "Unit", DataType: "string", IsSystem: false},
 		{Name: "threshold", Label: "Threshold", DataType: "decimal", IsSystem: false},
+		{Name: "unit_cost", Label: "Unit Cost", DataType: "decimal", IsSystem: true},
+		{Name: "has_nutrition", Label: "Has Nutrition", DataType: "boolean", IsSystem: true},
+		{Name: "kcal", Label: "Kcal", DataType: "decimal", IsSystem: false},
+		{Name: "protein", Label: "Protein (g)", DataType: "decimal", IsSystem: false},
+		{Name: "fat", Label: "Fat (g)", DataType: "decimal", IsSystem: false},
+		{Name: "carbs", Label: "Carbs (g)", DataType: "decimal", IsSystem: false},
+		{Name: "sodium", Label: "Sodium (mg)", DataType: "decimal", IsSystem: false},
 	}
 }
\ No newline at end of file
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// ModifierGroups is a set of options customers pick from, such as toppings or
+// spice level. A group can be attached to many products.
+type ModifierGroups struct {
+	ID        int64             `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string            `gorm:"type:varchar(100);not null" json:"name"`
+	MinSelect int               `gorm:"not null;default:0" json:"min_select"`
+	MaxSelect int               `gorm:"not null;default:0" json:"max_select"`
+	Required  bool              `gorm:"not null;default:false" json:"required"`
+	Options   []ModifierOptions `gorm:"foreignKey:GroupID" json:"options"`
+	CreatedAt time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (ModifierGroups) TableName() string {
+	return "modifier_groups"
+}
+
+func (ModifierGroups) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "min_select", Label: "Min Select", DataType: "integer", IsSystem: false},
+		{Name: "max_select", Label: "Max Select", DataType: "integer", IsSystem: false},
+		{Name: "required", Label: "Required", DataType: "boolean", IsSystem: false},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+type ModifierOptions struct {
+	ID         int64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	GroupID    int64           `gorm:"not null;index" json:"group_id"`
+	Group      *ModifierGroups `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+	Name       string          `gorm:"type:varchar(100);not null" json:"name"`
+	PriceDelta float64         `gorm:"not null;default:0" json:"price_delta"`
+	Status     bool            `gorm:"not null" json:"status"`
+	SortOrder  int             `gorm:"not null;default:0" json:"sort_order"`
+}
+
+func (ModifierOptions) TableName() string {
+	return "modifier_options"
+}
+
+func (ModifierOptions) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "group_id", Label: "Group ID", DataType: "integer", IsSystem: true},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "price_delta", Label: "Price Delta", DataType: "decimal", IsSystem: false},
+		{Name: "status", Label: "Status", DataType: "boolean", IsSystem: false},
+		{Name: "sort_order", Label: "Sort Order", DataType: "integer", IsSystem: false},
+	}
+}
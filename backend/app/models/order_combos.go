@@ -0,0 +1,29 @@
+package models
+
+// OrderCombos records a combo sold in an order at its bundle price. The
+// component products are order items with order_combo_id set, their unit
+// prices are the bundle price spread over the components.
+type OrderCombos struct {
+	ID        int64        `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID   int64        `gorm:"not null;index" json:"order_id"`
+	ComboID   int64        `gorm:"not null" json:"combo_id"`
+	Name      string       `gorm:"type:varchar(150);not null" json:"name"`
+	Quantity  int          `gorm:"not null" json:"quantity"`
+	UnitPrice float64      `gorm:"not null" json:"unit_price"`
+	Items     []OrderItems `gorm:"foreignKey:OrderComboID" json:"items"`
+}
+
+func (OrderCombos) TableName() string {
+	return "order_combos"
+}
+
+func (OrderCombos) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "order_id", Label: "Order ID", DataType: "integer", IsSystem: true},
+		{Name: "combo_id", Label: "Combo ID", DataType: "integer", IsSystem: true},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: true},
+		{Name: "quantity", Label: "Quantity", DataType: "integer", IsSystem: true},
+		{Name: "unit_price", Label: "Unit Price", DataType: "decimal", IsSystem: true},
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+// OrderItemModifiers is a snapshot of a chosen modifier taken when the order
+// was placed, so later menu edits do not change what the kitchen and the
+// invoice show.
+type OrderItemModifiers struct {
+	ID               int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderItemID      int64   `gorm:"not null;index" json:"order_item_id"`
+	ModifierOptionID int64   `gorm:"not null" json:"modifier_option_id"`
+	GroupName        string  `gorm:"type:varchar(100);not null" json:"group_name"`
+	OptionName       string  `gorm:"type:varchar(100);not null" json:"option_name"`
+	PriceDelta       float64 `gorm:"not null;default:0" json:"price_delta"`
+}
+
+func (OrderItemModifiers) TableName() string {
+	return "order_item_modifiers"
+}
+
+func (OrderItemModifiers) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "order_item_id", Label: "Order Item ID", DataType: "integer", IsSystem: true},
+		{Name: "modifier_option_id", Label: "Modifier Option ID", DataType: "integer", IsSystem: true},
+		{Name: "group_name", Label: "Group Name", DataType: "string", IsSystem: true},
+		{Name: "option_name", Label: "Option Name", DataType: "string", IsSystem: true},
+		{Name: "price_delta", Label: "Price Delta", DataType: "decimal", IsSystem: true},
+	}
+}
@@ -6,8 +6,14 @@ type OrderItems struct {
 	Order Orders `gorm:"foreignKey:OrderID" json:"order"`
 	ProductID int64 `gorm:"not null" json:"product_id"`
 	Product Product `gorm:"foreignKey:ProductID" json:"product"`
+	VariantID *int64 `json:"variant_id"`
+	Variant *ProductVariants `gorm:"foreignKey:VariantID" json:"variant"`
+	VariantName string `gorm:"type:varchar(100)" json:"variant_name"`
+	RecipeFactor float64 `gorm:"not null;default:1" json:"recipe_factor"`
 	Quantity int `gorm:"not null" json:"quantity"`
 	UnitPrice float64 `gorm:"not null" json:"unit_price"`
+	Modifiers []OrderItemModifiers `gorm:"foreignKey:OrderItemID" json:"modifiers"`
+	OrderComboID *int64 `json:"order_combo_id"`
 }
 
 func (OrderItems) TableName() string {
@@ -19,7 +25,11 @@ func (OrderItems) GetFields() []Field {
 		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
 		{Name: "order_id", Label: "Order ID", DataType: "integer", IsSystem: false},
 		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: false},
+		{Name: "variant_id", Label: "Variant ID", DataType: "integer", IsSystem: false},
+		{Name: "variant_name", Label: "Variant Name", DataType: "string", IsSystem: true},
+		{Name: "recipe_factor", Label: "Recipe Factor", DataType: "decimal", IsSystem: true},
 		{Name: "quantity", Label: "Quantity", DataType: "integer", IsSystem: false},
 		{Name: "unit_price", Label: "Unit Price", DataType: "decimal", IsSystem: false},
+		{Name: "order_combo_id", Label: "Order Combo ID", DataType: "integer", IsSystem: true},
 	}
 }
\ No newline at end of file
@@ -16,6 +16,7 @@ type Orders struct {
 	Discount float64 `gorm:"not null;default:0" json:"discount"`
 	PaymentMethod string `gorm:"type:varchar(50);not null" json:"payment_method"`
 	Status string `gorm:"type:varchar(50);not null" json:"status"`
+	StockDeducted bool `gorm:"not null;default:false" json:"stock_deducted"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
 
@@ -35,6 +36,7 @@ func (Orders) GetFields() []Field {
 		{Name: "discount", Label: "Discount", DataType: "decimal", IsSystem: false},
 		{Name: "payment_method", Label: "Payment Method", DataType: "string", IsSystem: false},
 		{Name: "status", Label: "Status", DataType: "string", IsSystem: false},
+		{Name: "stock_deducted", Label: "Stock Deducted", DataType: "boolean", IsSystem: true},
 		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: false},
 	}
 }
\ No newline at end of file
@@ -1,9 +1,10 @@
 package models
 
 type ProductIngredient struct {
-	ProductID int64 `gorm:"primaryKey;column:product_id"`
-	IngredientID int64 `gorm:"primaryKey;column:ingredient_id"`
-	AmountUsed float64 `gorm:"not null;column:amount_used" json:"amount_used"`
+	ProductID    int64       `gorm:"primaryKey;column:product_id" json:"product_id"`
+	IngredientID int64       `gorm:"primaryKey;column:ingredient_id" json:"ingredient_id"`
+	Ingredient   Ingredients `gorm:"foreignKey:IngredientID" json:"ingredient"`
+	AmountUsed   float64     `gorm:"not null;column:amount_used" json:"amount_used"`
 }
 
 func (ProductIngredient) TableName() string {
@@ -16,4 +17,4 @@ func (ProductIngredient) GetFields() []Field {
 		{Name: "ingredient_id", Label: "Ingredient ID", DataType: "integer", IsSystem: true},
 		{Name: "amount_used", Label: "Amount Used", DataType: "decimal", IsSystem: false},
 	}
-}
\ No newline at end of file
+}
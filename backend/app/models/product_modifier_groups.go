@@ -0,0 +1,21 @@
+package models
+
+type ProductModifierGroups struct {
+	ID              int64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID       int64 `gorm:"not null;index" json:"product_id"`
+	ModifierGroupID int64 `gorm:"not null;index" json:"modifier_group_id"`
+	SortOrder       int   `gorm:"not null;default:0" json:"sort_order"`
+}
+
+func (ProductModifierGroups) TableName() string {
+	return "product_modifier_groups"
+}
+
+func (ProductModifierGroups) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: false},
+		{Name: "modifier_group_id", Label: "Modifier Group ID", DataType: "integer", IsSystem: false},
+		{Name: "sort_order", Label: "Sort Order", DataType: "integer", IsSystem: false},
+	}
+}
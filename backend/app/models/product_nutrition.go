@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// ProductNutrition is the nutrition of one portion of a product, summed from
+// its recipe. It is recalculated whenever the recipe or the nutrition of one
+// of its ingredients changes. Complete is false when some ingredients have no
+// nutrition values yet, the totals then only cover the others.
+type ProductNutrition struct {
+	ID                 int64     `gorm:"primaryKey;autoIncrement" json:"-"`
+	ProductID          int64     `gorm:"not null;uniqueIndex" json:"product_id"`
+	Kcal               float64   `gorm:"not null;default:0" json:"kcal"`
+	Protein            float64   `gorm:"not null;default:0" json:"protein"`
+	Fat                float64   `gorm:"not null;default:0" json:"fat"`
+	Carbs              float64   `gorm:"not null;default:0" json:"carbs"`
+	Sodium             float64   `gorm:"not null;default:0" json:"sodium"`
+	Complete           bool      `gorm:"not null" json:"complete"`
+	MissingIngredients int       `gorm:"not null;default:0" json:"missing_ingredients"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (ProductNutrition) TableName() string {
+	return "product_nutrition"
+}
+
+func (ProductNutrition) GetFields() []Field {
+	return []Field{
+		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: true},
+		{Name: "kcal", Label: "Kcal", DataType: "decimal", IsSystem: true},
+		{Name: "protein", Label: "Protein (g)", DataType: "decimal", IsSystem: true},
+		{Name: "fat", Label: "Fat (g)", DataType: "decimal", IsSystem: true},
+		{Name: "carbs", Label: "Carbs (g)", DataType: "decimal", IsSystem: true},
+		{Name: "sodium", Label: "Sodium (mg)", DataType: "decimal", IsSystem: true},
+		{Name: "complete", Label: "Complete", DataType: "boolean", IsSystem: true},
+		{Name: "missing_ingredients", Label: "Missing Ingredients", DataType: "integer", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
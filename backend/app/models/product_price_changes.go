@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// ProductPriceChanges is a price an admin scheduled for a product. Pending
+// changes are applied by the products:apply-price-changes command once
+// EffectiveAt has passed.
+type ProductPriceChanges struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID   int64      `gorm:"not null;index" json:"product_id"`
+	Price       float64    `gorm:"not null" json:"price"`
+	EffectiveAt time.Time  `gorm:"not null" json:"effective_at"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Note        string     `gorm:"type:text" json:"note"`
+	CreatedBy   int64      `gorm:"not null" json:"created_by"`
+	AppliedAt   *time.Time `json:"applied_at"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (ProductPriceChanges) TableName() string {
+	return "product_price_changes"
+}
+
+func (ProductPriceChanges) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: false},
+		{Name: "price", Label: "Price", DataType: "decimal", IsSystem: false},
+		{Name: "effective_at", Label: "Effective At", DataType: "timestamp", IsSystem: false},
+		{Name: "status", Label: "Status", DataType: "string", IsSystem: true},
+		{Name: "note", Label: "Note", DataType: "string", IsSystem: false},
+		{Name: "created_by", Label: "Created By", DataType: "integer", IsSystem: true},
+		{Name: "applied_at", Label: "Applied At", DataType: "timestamp", IsSystem: true},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
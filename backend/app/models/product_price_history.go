@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ProductPriceHistory is the price a product had between StartedAt and
+// EndedAt. The current price is the row without EndedAt.
+type ProductPriceHistory struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID int64      `gorm:"not null;index" json:"product_id"`
+	Price     float64    `gorm:"not null" json:"price"`
+	StartedAt time.Time  `gorm:"not null" json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Source    string     `gorm:"type:varchar(20);not null" json:"source"`
+	ChangedBy *int64     `json:"changed_by"`
+}
+
+func (ProductPriceHistory) TableName() string {
+	return "product_price_history"
+}
+
+func (ProductPriceHistory) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: true},
+		{Name: "price", Label: "Price", DataType: "decimal", IsSystem: true},
+		{Name: "started_at", Label: "Started At", DataType: "timestamp", IsSystem: true},
+		{Name: "ended_at", Label: "Ended At", DataType: "timestamp", IsSystem: true},
+		{Name: "source", Label: "Source", DataType: "string", IsSystem: true},
+		{Name: "changed_by", Label: "Changed By", DataType: "integer", IsSystem: true},
+	}
+}
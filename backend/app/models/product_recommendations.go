@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// ProductRecommendations is a product often ordered together with ProductID,
+// computed from completed orders by the products:compute-recommendations
+// command. Confidence is the share of ProductID's orders that also contain
+// the recommended product, Lift compares that with how often the recommended
+// product is ordered at all.
+type ProductRecommendations struct {
+	ID                   int64     `gorm:"primaryKey;autoIncrement" json:"-"`
+	ProductID            int64     `gorm:"not null;index" json:"product_id"`
+	RecommendedProductID int64     `gorm:"not null" json:"recommended_product_id"`
+	Orders               int       `gorm:"not null" json:"orders"`
+	Confidence           float64   `gorm:"not null" json:"confidence"`
+	Lift                 float64   `gorm:"not null" json:"lift"`
+	Rank                 int       `gorm:"not null" json:"rank"`
+	ComputedAt           time.Time `gorm:"not null" json:"computed_at"`
+}
+
+func (ProductRecommendations) TableName() string {
+	return "product_recommendations"
+}
+
+func (ProductRecommendations) GetFields() []Field {
+	return []Field{
+		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: true},
+		{Name: "recommended_product_id", Label: "Recommended Product ID", DataType: "integer", IsSystem: true},
+		{Name: "orders", Label: "Orders", DataType: "integer", IsSystem: true},
+		{Name: "confidence", Label: "Confidence", DataType: "decimal", IsSystem: true},
+		{Name: "lift", Label: "Lift", DataType: "decimal", IsSystem: true},
+		{Name: "rank", Label: "Rank", DataType: "integer", IsSystem: true},
+		{Name: "computed_at", Label: "Computed At", DataType: "timestamp", IsSystem: true},
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+type ProductTags struct {
+	ID        int64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID int64 `gorm:"not null;index" json:"product_id"`
+	TagID     int64 `gorm:"not null;index" json:"tag_id"`
+	Tag       Tags  `gorm:"foreignKey:TagID" json:"tag"`
+}
+
+func (ProductTags) TableName() string {
+	return "product_tags"
+}
+
+func (ProductTags) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: false},
+		{Name: "tag_id", Label: "Tag ID", DataType: "integer", IsSystem: false},
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ProductTranslations holds the name and description of a product in one
+// locale. Missing translations fall back to the product's own columns.
+type ProductTranslations struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID   int64     `gorm:"not null;index" json:"product_id"`
+	Locale      string    `gorm:"type:varchar(10);not null" json:"locale"`
+	Name        string    `gorm:"not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (ProductTranslations) TableName() string {
+	return "product_translations"
+}
+
+func (ProductTranslations) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: false},
+		{Name: "locale", Label: "Locale", DataType: "string", IsSystem: false},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "description", Label: "Description", DataType: "string", IsSystem: false},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
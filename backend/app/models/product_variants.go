@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// ProductVariants is a sellable size or portion of a product. RecipeFactor
+// scales the product recipe, so a large portion at 1.5 uses half as much
+// again of every ingredient.
+type ProductVariants struct {
+	ID           int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID    int64   `gorm:"not null;index" json:"product_id"`
+	Name         string  `gorm:"type:varchar(100);not null" json:"name"`
+	SKU          string  `gorm:"column:sku;type:varchar(64);not null;unique" json:"sku"`
+	Price        float64 `gorm:"not null" json:"price"`
+	RecipeFactor float64 `gorm:"not null;default:1" json:"recipe_factor"`
+	IsDefault    bool    `gorm:"not null;default:false" json:"is_default"`
+	Status       bool    `gorm:"not null" json:"status"`
+	SortOrder    int     `gorm:"not null;default:0" json:"sort_order"`
+	// Nutrition is the product nutrition scaled by RecipeFactor, filled by the controllers
+	Nutrition *ProductNutrition `gorm:"-" json:"nutrition,omitempty"`
+	CreatedAt time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (ProductVariants) TableName() string {
+	return "product_variants"
+}
+
+func (ProductVariants) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "product_id", Label: "Product ID", DataType: "integer", IsSystem: true},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "sku", Label: "SKU", DataType: "string", IsSystem: false},
+		{Name: "price", Label: "Price", DataType: "decimal", IsSystem: false},
+		{Name: "recipe_factor", Label: "Recipe Factor", DataType: "decimal", IsSystem: false},
+		{Name: "is_default", Label: "Is Default", DataType: "boolean", IsSystem: false},
+		{Name: "status", Label: "Status", DataType: "boolean", IsSystem: false},
+		{Name: "sort_order", Label: "Sort Order", DataType: "integer", IsSystem: false},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
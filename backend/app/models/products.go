@@ -8,17 +8,27 @@ import (
 
 type Product struct {
 	orm.Model
-	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
-	CategoryID  int64     `gorm:"not null" json:"category_id"`
-	Category    Category  `gorm:"foreignKey:CategoryID" json:"category"`
-	Name        string    `gorm:"not null" json:"name"`
-	Description string    `gorm:"not null" json:"description"`
-	Price       float64   `gorm:"not null" json:"price"`
-	Thumbnail   string    `gorm:"not null" json:"thumbnail"`
-	Status      bool      `gorm:"not null" json:"status"`
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt   time.Time `gorm:"index" json:"deleted_at"`
+	orm.SoftDeletes
+	ID          int64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	CategoryID  int64    `gorm:"not null" json:"category_id"`
+	Category    Category `gorm:"foreignKey:CategoryID" json:"category"`
+	Name        string   `gorm:"not null" json:"name"`
+	Description string   `gorm:"not null" json:"description"`
+	Price       float64  `gorm:"not null" json:"price"`
+	Thumbnail   string   `gorm:"not null" json:"thumbnail"`
+	Status      bool     `gorm:"not null" json:"status"`
+	Available   bool     `gorm:"-" json:"available"`
+	// AvailabilityScheduleID limits when the product is sold, falling back to the category schedule
+	AvailabilityScheduleID *int64            `json:"availability_schedule_id"`
+	Variants               []ProductVariants `gorm:"foreignKey:ProductID" json:"variants"`
+	// ModifierGroups is filled by the controllers, groups are linked through product_modifier_groups
+	ModifierGroups []ModifierGroups `gorm:"-" json:"modifier_groups"`
+	// Nutrition of one portion, kept up to date from the recipe
+	Nutrition *ProductNutrition `gorm:"foreignKey:ProductID" json:"nutrition"`
+	// Tags is filled by the controllers, including allergens derived from the recipe
+	Tags      []Tags    `gorm:"-" json:"tags"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 func (Product) TableName() string {
@@ -34,6 +44,7 @@ func (Product) GetFields() []Field {
 		{Name: "price", Label: "Price", DataType: "decimal", IsSystem: false},
 		{Name: "thumbnail", Label: "Thumbnail", DataType: "string", IsSystem: false},
 		{Name: "status", Label: "Status", DataType: "boolean", IsSystem: false},
+		{Name: "availability_schedule_id", Label: "Availability Schedule ID", DataType: "integer", IsSystem: false},
 		{Name: "created_at", Label: "Created At", DataType: "datetime", IsSystem: true},
 		{Name: "updated_at", Label: "Updated At", DataType: "datetime", IsSystem: true},
 		{Name: "deleted_at", Label: "Deleted At", DataType: "datetime", IsSystem: true},
@@ -0,0 +1,26 @@
+package models
+
+type PurchaseOrderLines struct {
+	ID               int64       `gorm:"primaryKey;autoIncrement" json:"id"`
+	PurchaseOrderID  int64       `gorm:"not null" json:"purchase_order_id"`
+	IngredientID     int64       `gorm:"not null" json:"ingredient_id"`
+	Ingredient       Ingredients `gorm:"foreignKey:IngredientID" json:"ingredient"`
+	Quantity         float64     `gorm:"not null" json:"quantity"`
+	UnitCost         float64     `gorm:"not null" json:"unit_cost"`
+	ReceivedQuantity float64     `gorm:"not null;default:0" json:"received_quantity"`
+}
+
+func (PurchaseOrderLines) TableName() string {
+	return "purchase_order_lines"
+}
+
+func (PurchaseOrderLines) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "purchase_order_id", Label: "Purchase Order ID", DataType: "integer", IsSystem: true},
+		{Name: "ingredient_id", Label: "Ingredient ID", DataType: "integer", IsSystem: false},
+		{Name: "quantity", Label: "Quantity", DataType: "decimal", IsSystem: false},
+		{Name: "unit_cost", Label: "Unit Cost", DataType: "decimal", IsSystem: false},
+		{Name: "received_quantity", Label: "Received Quantity", DataType: "decimal", IsSystem: true},
+	}
+}
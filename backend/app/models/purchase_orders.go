@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+type PurchaseOrders struct {
+	ID         int64                `gorm:"primaryKey;autoIncrement" json:"id"`
+	SupplierID int64                `gorm:"not null" json:"supplier_id"`
+	Supplier   Suppliers            `gorm:"foreignKey:SupplierID" json:"supplier"`
+	Status     string               `gorm:"type:varchar(20);not null;default:'draft'" json:"status"`
+	Note       string               `gorm:"type:text" json:"note"`
+	Total      float64              `gorm:"not null;default:0" json:"total"`
+	CreatedBy  int64                `gorm:"not null" json:"created_by"`
+	SentAt     *time.Time           `gorm:"type:timestamp" json:"sent_at"`
+	ReceivedAt *time.Time           `gorm:"type:timestamp" json:"received_at"`
+	Lines      []PurchaseOrderLines `gorm:"foreignKey:PurchaseOrderID" json:"lines"`
+	CreatedAt  time.Time            `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (PurchaseOrders) TableName() string {
+	return "purchase_orders"
+}
+
+func (PurchaseOrders) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "supplier_id", Label: "Supplier ID", DataType: "integer", IsSystem: false},
+		{Name: "status", Label: "Status", DataType: "string", IsSystem: true},
+		{Name: "note", Label: "Note", DataType: "text", IsSystem: false},
+		{Name: "total", Label: "Total", DataType: "decimal", IsSystem: true},
+		{Name: "created_by", Label: "Created By", DataType: "integer", IsSystem: true},
+		{Name: "sent_at", Label: "Sent At", DataType: "datetime", IsSystem: true},
+		{Name: "received_at", Label: "Received At", DataType: "datetime", IsSystem: true},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
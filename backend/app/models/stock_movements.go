@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+type StockMovements struct {
+	ID            int64       `gorm:"primaryKey;autoIncrement" json:"id"`
+	IngredientID  int64       `gorm:"not null" json:"ingredient_id"`
+	Ingredient    Ingredients `gorm:"foreignKey:IngredientID" json:"ingredient"`
+	Type          string      `gorm:"type:varchar(30);not null" json:"type"`
+	Change        float64     `gorm:"not null" json:"change"`
+	QuantityAfter float64     `gorm:"not null" json:"quantity_after"`
+	OrderID       *int64      `gorm:"index" json:"order_id"`
+	BatchID       *int64      `gorm:"index" json:"batch_id"`
+	Reason        string      `gorm:"type:text" json:"reason"`
+	UserID        int64       `gorm:"not null" json:"user_id"`
+	CreatedAt     time.Time   `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (StockMovements) TableName() string {
+	return "stock_movements"
+}
+
+func (StockMovements) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "ingredient_id", Label: "Ingredient ID", DataType: "integer", IsSystem: false},
+		{Name: "type", Label: "Type", DataType: "string", IsSystem: false},
+		{Name: "change", Label: "Change", DataType: "decimal", IsSystem: false},
+		{Name: "quantity_after", Label: "Quantity After", DataType: "decimal", IsSystem: true},
+		{Name: "order_id", Label: "Order ID", DataType: "integer", IsSystem: true},
+		{Name: "batch_id", Label: "Batch ID", DataType: "integer", IsSystem: true},
+		{Name: "reason", Label: "Reason", DataType: "text", IsSystem: false},
+		{Name: "user_id", Label: "User ID", DataType: "integer", IsSystem: true},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// StocktakeLines holds one counted ingredient. ExpectedQuantity is the system
+// stock at the moment the count was entered; UnitCost is captured on close.
+type StocktakeLines struct {
+	ID               int64       `gorm:"primaryKey;autoIncrement" json:"id"`
+	StocktakeID      int64       `gorm:"not null" json:"stocktake_id"`
+	IngredientID     int64       `gorm:"not null" json:"ingredient_id"`
+	Ingredient       Ingredients `gorm:"foreignKey:IngredientID" json:"ingredient"`
+	ExpectedQuantity float64     `gorm:"not null" json:"expected_quantity"`
+	CountedQuantity  float64     `gorm:"not null" json:"counted_quantity"`
+	UnitCost         float64     `gorm:"not null;default:0" json:"unit_cost"`
+	CountedBy        int64       `gorm:"not null" json:"counted_by"`
+	CountedAt        time.Time   `gorm:"type:timestamp" json:"counted_at"`
+}
+
+func (StocktakeLines) TableName() string {
+	return "stocktake_lines"
+}
+
+func (StocktakeLines) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "stocktake_id", Label: "Stocktake ID", DataType: "integer", IsSystem: true},
+		{Name: "ingredient_id", Label: "Ingredient ID", DataType: "integer", IsSystem: false},
+		{Name: "expected_quantity", Label: "Expected Quantity", DataType: "decimal", IsSystem: true},
+		{Name: "counted_quantity", Label: "Counted Quantity", DataType: "decimal", IsSystem: false},
+		{Name: "unit_cost", Label: "Unit Cost", DataType: "decimal", IsSystem: true},
+		{Name: "counted_by", Label: "Counted By", DataType: "integer", IsSystem: true},
+		{Name: "counted_at", Label: "Counted At", DataType: "datetime", IsSystem: true},
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type Stocktakes struct {
+	ID        int64            `gorm:"primaryKey;autoIncrement" json:"id"`
+	Status    string           `gorm:"type:varchar(20);not null;default:'open'" json:"status"`
+	Note      string           `gorm:"type:text" json:"note"`
+	OpenedBy  int64            `gorm:"not null" json:"opened_by"`
+	ClosedBy  *int64           `json:"closed_by"`
+	ClosedAt  *time.Time       `gorm:"type:timestamp" json:"closed_at"`
+	Lines     []StocktakeLines `gorm:"foreignKey:StocktakeID" json:"lines"`
+	CreatedAt time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Stocktakes) TableName() string {
+	return "stocktakes"
+}
+
+func (Stocktakes) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "status", Label: "Status", DataType: "string", IsSystem: true},
+		{Name: "note", Label: "Note", DataType: "text", IsSystem: false},
+		{Name: "opened_by", Label: "Opened By", DataType: "integer", IsSystem: true},
+		{Name: "closed_by", Label: "Closed By", DataType: "integer", IsSystem: true},
+		{Name: "closed_at", Label: "Closed At", DataType: "datetime", IsSystem: true},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
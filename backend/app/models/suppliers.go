@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type Suppliers struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
+	ContactName string    `gorm:"type:varchar(100)" json:"contact_name"`
+	Phone       string    `gorm:"type:varchar(20)" json:"phone"`
+	Email       string    `gorm:"type:varchar(255)" json:"email"`
+	Address     string    `gorm:"type:text" json:"address"`
+	Note        string    `gorm:"type:text" json:"note"`
+	IsActive    bool      `gorm:"type:boolean;default:true" json:"is_active"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Suppliers) TableName() string {
+	return "suppliers"
+}
+
+func (Suppliers) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "contact_name", Label: "Contact Name", DataType: "string", IsSystem: false},
+		{Name: "phone", Label: "Phone", DataType: "string", IsSystem: false},
+		{Name: "email", Label: "Email", DataType: "string", IsSystem: false},
+		{Name: "address", Label: "Address", DataType: "text", IsSystem: false},
+		{Name: "note", Label: "Note", DataType: "text", IsSystem: false},
+		{Name: "is_active", Label: "Is Active", DataType: "boolean", IsSystem: false},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Tags is a menu label guests can filter on. Dietary tags (vegan, vegetarian)
+// describe the dish as a whole, allergen tags can also be derived from the
+// ingredients of a product's recipe.
+type Tags struct {
+	ID   int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name string `gorm:"type:varchar(100);not null" json:"name"`
+	Slug string `gorm:"type:varchar(100);not null;uniqueIndex" json:"slug"`
+	Type string `gorm:"type:varchar(20);not null" json:"type"`
+	// Derived is set in product responses when the tag comes from an ingredient
+	Derived   bool      `gorm:"-" json:"derived"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Tags) TableName() string {
+	return "tags"
+}
+
+func (Tags) GetFields() []Field {
+	return []Field{
+		{Name: "id", Label: "ID", DataType: "integer", IsSystem: true},
+		{Name: "name", Label: "Name", DataType: "string", IsSystem: false},
+		{Name: "slug", Label: "Slug", DataType: "string", IsSystem: false},
+		{Name: "type", Label: "Type", DataType: "string", IsSystem: false},
+		{Name: "created_at", Label: "Created At", DataType: "timestamp", IsSystem: true},
+		{Name: "updated_at", Label: "Updated At", DataType: "timestamp", IsSystem: true},
+	}
+}
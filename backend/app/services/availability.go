@@ -0,0 +1,52 @@
+package services
+
+import (
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+// unavailableProductIDs selects products that have at least one recipe
+// ingredient whose stock cannot cover a single portion.
+const unavailableProductIDs = `SELECT product_ingredients.product_id FROM product_ingredients
+	JOIN ingredients ON ingredients.id = product_ingredients.ingredient_id
+	WHERE ingredients.quantity < product_ingredients.amount_used`
+
+// Sellable is a query scope that keeps only products the kitchen can currently make.
+// Products without a recipe are always considered sellable.
+func Sellable(query orm.Query) orm.Query {
+	return query.Where("products.id NOT IN (" + unavailableProductIDs + ")")
+}
+
+// UnavailableProductIDs returns the products that cannot be made with the current stock.
+func UnavailableProductIDs() (map[int64]bool, error) {
+	var ids []int64
+	if err := facades.Orm().Query().Raw(unavailableProductIDs).Scan(&ids); err != nil {
+		return nil, err
+	}
+
+	unavailable := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		unavailable[id] = true
+	}
+
+	return unavailable, nil
+}
+
+// CanMakeProduct reports whether the current stock covers the given number of
+// recipe portions of the product (quantity times the variant's recipe factor).
+func CanMakeProduct(productID int64, portions float64) (bool, error) {
+	var recipe []models.ProductIngredient
+	if err := facades.Orm().Query().Where("product_id = ?", productID).With("Ingredient").Find(&recipe); err != nil {
+		return false, err
+	}
+
+	for _, item := range recipe {
+		if item.Ingredient.Quantity < item.AmountUsed*portions {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
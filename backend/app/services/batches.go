@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+const NotificationExpiringStock = "expiring_stock"
+
+// ExpiringBatches returns batches with stock left that expire within the given
+// number of days, already expired ones included, soonest first.
+func ExpiringBatches(days int) ([]models.IngredientBatches, error) {
+	until := time.Now().AddDate(0, 0, days)
+
+	batches := []models.IngredientBatches{}
+	err := facades.Orm().Query().
+		Where("remaining > 0 AND expires_at IS NOT NULL AND expires_at <= ?", until).
+		With("Ingredient").
+		OrderBy("expires_at").
+		Find(&batches)
+
+	return batches, err
+}
+
+// DiscardBatch writes off whatever is left of a batch as waste.
+func DiscardBatch(tx orm.Query, batchID int64, reason string, userID int64) ([]models.StockMovements, error) {
+	var batch models.IngredientBatches
+	if err := tx.Where("id = ?", batchID).First(&batch); err != nil {
+		return nil, err
+	}
+	if batch.ID == 0 {
+		return nil, ErrBatchNotFound
+	}
+	if batch.Remaining <= 0 {
+		return []models.StockMovements{}, nil
+	}
+	if reason == "" {
+		reason = fmt.Sprintf("Batch #%d discarded", batch.ID)
+	}
+
+	return applyMovement(tx, models.StockMovements{
+		IngredientID: batch.IngredientID,
+		Type:         StockMovementWaste,
+		Change:       -batch.Remaining,
+		BatchID:      &batch.ID,
+		Reason:       reason,
+		UserID:       userID,
+	}, nil)
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+// guestCartTokenTTL is how long a visitor can come back to a cart they built
+// without signing in.
+const guestCartTokenTTL = 30 * 24 * time.Hour
+
+var ErrInvalidCartToken = errors.New("guest cart token is invalid or expired")
+
+// IssueGuestCartToken signs the id of a guest cart. The token carries no
+// subject, so it is never accepted where a login token is expected.
+func IssueGuestCartToken(cartID int64) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"cart_id": cartID,
+		"typ":     "guest_cart",
+		"exp":     time.Now().Add(guestCartTokenTTL).Unix(),
+	})
+
+	return token.SignedString([]byte(facades.Config().GetString("jwt.secret")))
+}
+
+// GuestCartFromToken returns the active guest cart a token was issued for.
+// Carts that were merged into a user's cart no longer match.
+func GuestCartFromToken(tokenString string) (models.Carts, error) {
+	var cart models.Carts
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(facades.Config().GetString("jwt.secret")), nil
+	})
+	if err != nil || !token.Valid {
+		return cart, ErrInvalidCartToken
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != "guest_cart" {
+		return cart, ErrInvalidCartToken
+	}
+	cartID, ok := claims["cart_id"].(float64)
+	if !ok {
+		return cart, ErrInvalidCartToken
+	}
+
+	if err := facades.Orm().Query().Where("id = ? AND user_id IS NULL AND status = ?", int64(cartID), "active").First(&cart); err != nil {
+		return cart, err
+	}
+	if cart.ID == 0 {
+		return cart, ErrInvalidCartToken
+	}
+
+	return cart, nil
+}
+
+// SetCartComboQuantity changes how many of a combo are in the cart and scales
+// its component lines to match.
+func SetCartComboQuantity(tx orm.Query, cartCombo models.CartCombos, quantity int) error {
+	if _, err := tx.Model(&models.CartCombos{}).Where("id = ?", cartCombo.ID).Update("quantity", quantity); err != nil {
+		return err
+	}
+	for _, item := range cartCombo.Items {
+		perCombo := item.Quantity / cartCombo.Quantity
+		if _, err := tx.Model(&models.CartItem{}).Where("id = ?", item.ID).Update("quantity", perCombo*quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MergeGuestCart moves the lines of a guest cart into the user's active cart.
+// A line the user already has, same product, size and modifiers or same combo
+// and choices, gets the guest quantity added; the others move over as they
+// are. A user without an active cart simply takes over the guest cart.
+func MergeGuestCart(tx orm.Query, guestCartID, userID int64) error {
+	var guestCart models.Carts
+	if err := tx.LockForUpdate().Where("id = ? AND user_id IS NULL AND status = ?", guestCartID, "active").First(&guestCart); err != nil {
+		return err
+	}
+	if guestCart.ID == 0 {
+		return ErrInvalidCartToken
+	}
+
+	var cart models.Carts
+	if err := tx.Where("user_id = ? AND status = ?", userID, "active").First(&cart); err != nil {
+		return err
+	}
+	if cart.ID == 0 {
+		_, err := tx.Model(&models.Carts{}).Where("id = ?", guestCartID).Update("user_id", userID)
+		return err
+	}
+
+	var guestItems, userItems []models.CartItem
+	if err := tx.Where("cart_id = ? AND cart_combo_id IS NULL", guestCartID).With("Modifiers").Find(&guestItems); err != nil {
+		return err
+	}
+	if err := tx.Where("cart_id = ? AND cart_combo_id IS NULL", cart.ID).With("Modifiers").Find(&userItems); err != nil {
+		return err
+	}
+	for _, item := range guestItems {
+		if existing, found := matchingCartItem(userItems, item); found {
+			if _, err := tx.Model(&models.CartItem{}).Where("id = ?", existing.ID).Update("quantity", existing.Quantity+item.Quantity); err != nil {
+				return err
+			}
+			if err := DeleteCartItems(tx, "id = ?", item.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := tx.Model(&models.CartItem{}).Where("id = ?", item.ID).Update("cart_id", cart.ID); err != nil {
+			return err
+		}
+	}
+
+	var guestCombos, userCombos []models.CartCombos
+	if err := tx.Where("cart_id = ?", guestCartID).With("Items").Find(&guestCombos); err != nil {
+		return err
+	}
+	if err := tx.Where("cart_id = ?", cart.ID).With("Items").Find(&userCombos); err != nil {
+		return err
+	}
+	for _, cartCombo := range guestCombos {
+		if existing, found := matchingCartCombo(userCombos, cartCombo); found {
+			if err := SetCartComboQuantity(tx, existing, existing.Quantity+cartCombo.Quantity); err != nil {
+				return err
+			}
+			if err := DeleteCartCombos(tx, "id = ?", cartCombo.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := tx.Model(&models.CartCombos{}).Where("id = ?", cartCombo.ID).Update("cart_id", cart.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Model(&models.CartItem{}).Where("cart_combo_id = ?", cartCombo.ID).Update("cart_id", cart.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Model(&models.Carts{}).Where("id = ?", guestCartID).Delete()
+
+	return err
+}
+
+// matchingCartItem finds the standalone line among items with the same
+// product, size and modifiers as item.
+func matchingCartItem(items []models.CartItem, item models.CartItem) (models.CartItem, bool) {
+	options := make([]models.ModifierOptions, 0, len(item.Modifiers))
+	for _, modifier := range item.Modifiers {
+		options = append(options, models.ModifierOptions{ID: modifier.ModifierOptionID})
+	}
+	for _, existing := range items {
+		if existing.ProductID == item.ProductID && sameVariant(existing.VariantID, item.VariantID) && SameModifiers(existing, options) {
+			return existing, true
+		}
+	}
+
+	return models.CartItem{}, false
+}
+
+// matchingCartCombo finds the combo among cartCombos with the same combo and
+// slot choices as cartCombo.
+func matchingCartCombo(cartCombos []models.CartCombos, cartCombo models.CartCombos) (models.CartCombos, bool) {
+	options := make([]models.ComboSlotOptions, 0, len(cartCombo.Items))
+	for _, item := range cartCombo.Items {
+		if item.ComboSlotOptionID != nil {
+			options = append(options, models.ComboSlotOptions{ID: *item.ComboSlotOptionID})
+		}
+	}
+	for _, existing := range cartCombos {
+		if existing.ComboID == cartCombo.ComboID && SameComboChoices(existing, options) {
+			return existing, true
+		}
+	}
+
+	return models.CartCombos{}, false
+}
+
+func sameVariant(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return *a == *b
+}
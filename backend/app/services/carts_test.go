@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"goravel/app/models"
+)
+
+type CartsTestSuite struct {
+	suite.Suite
+}
+
+func TestCartsTestSuite(t *testing.T) {
+	suite.Run(t, new(CartsTestSuite))
+}
+
+func (s *CartsTestSuite) TestMergeFindsLineWithSameProductSizeAndModifiers() {
+	large := int64(2)
+	userItems := []models.CartItem{
+		{ID: 1, ProductID: 5, Quantity: 1},
+		{ID: 2, ProductID: 5, VariantID: &large, Quantity: 1, Modifiers: []models.CartItemModifiers{{ModifierOptionID: 21}, {ModifierOptionID: 11}}},
+	}
+
+	existing, found := matchingCartItem(userItems, models.CartItem{ProductID: 5, Quantity: 3})
+	s.True(found)
+	s.Equal(int64(1), existing.ID)
+
+	sameLarge := int64(2)
+	existing, found = matchingCartItem(userItems, models.CartItem{ProductID: 5, VariantID: &sameLarge,
+		Modifiers: []models.CartItemModifiers{{ModifierOptionID: 11}, {ModifierOptionID: 21}}})
+	s.True(found)
+	s.Equal(int64(2), existing.ID)
+}
+
+func (s *CartsTestSuite) TestMergeMovesLinesThatDiffer() {
+	large, small := int64(2), int64(3)
+	userItems := []models.CartItem{
+		{ID: 1, ProductID: 5, VariantID: &large, Modifiers: []models.CartItemModifiers{{ModifierOptionID: 11}}},
+	}
+
+	_, found := matchingCartItem(userItems, models.CartItem{ProductID: 6, VariantID: &large, Modifiers: []models.CartItemModifiers{{ModifierOptionID: 11}}})
+	s.False(found)
+	_, found = matchingCartItem(userItems, models.CartItem{ProductID: 5, VariantID: &small, Modifiers: []models.CartItemModifiers{{ModifierOptionID: 11}}})
+	s.False(found)
+	_, found = matchingCartItem(userItems, models.CartItem{ProductID: 5, Modifiers: []models.CartItemModifiers{{ModifierOptionID: 11}}})
+	s.False(found)
+	_, found = matchingCartItem(userItems, models.CartItem{ProductID: 5, VariantID: &large})
+	s.False(found)
+	_, found = matchingCartItem(nil, models.CartItem{ProductID: 5})
+	s.False(found)
+}
+
+func (s *CartsTestSuite) TestMergeFindsComboWithSameChoices() {
+	burger, fries, cola, tea := int64(11), int64(12), int64(21), int64(22)
+	userCombos := []models.CartCombos{
+		{ID: 1, ComboID: 7, Quantity: 1, Items: []models.CartItem{{ComboSlotOptionID: &burger}, {ComboSlotOptionID: &cola}}},
+		{ID: 2, ComboID: 7, Quantity: 2, Items: []models.CartItem{{ComboSlotOptionID: &fries}, {ComboSlotOptionID: &tea}}},
+	}
+
+	existing, found := matchingCartCombo(userCombos, models.CartCombos{ComboID: 7, Items: []models.CartItem{{ComboSlotOptionID: &tea}, {ComboSlotOptionID: &fries}}})
+	s.True(found)
+	s.Equal(int64(2), existing.ID)
+
+	_, found = matchingCartCombo(userCombos, models.CartCombos{ComboID: 7, Items: []models.CartItem{{ComboSlotOptionID: &burger}, {ComboSlotOptionID: &tea}}})
+	s.False(found)
+	_, found = matchingCartCombo(userCombos, models.CartCombos{ComboID: 8, Items: []models.CartItem{{ComboSlotOptionID: &burger}, {ComboSlotOptionID: &cola}}})
+	s.False(found)
+}
+
+func (s *CartsTestSuite) TestSameVariant() {
+	a, b, c := int64(1), int64(1), int64(2)
+
+	s.True(sameVariant(nil, nil))
+	s.True(sameVariant(&a, &b))
+	s.False(sameVariant(&a, &c))
+	s.False(sameVariant(&a, nil))
+	s.False(sameVariant(nil, &a))
+}
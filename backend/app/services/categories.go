@@ -0,0 +1,160 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+var (
+	ErrCategoryParentNotFound = errors.New("parent category not found")
+	ErrCategoryCycle          = errors.New("a category cannot be placed under itself or one of its children")
+)
+
+// CategoryNode is a category in the menu tree. ProductCount counts the
+// products directly in the category, TotalProductCount includes its children.
+type CategoryNode struct {
+	models.Category
+	ProductCount      int64           `json:"product_count"`
+	TotalProductCount int64           `json:"total_product_count"`
+	Children          []*CategoryNode `json:"children"`
+}
+
+// CategoryTree nests categories under their parents, keeping the order they
+// are given in. Categories whose parent is not in the list are dropped, so
+// filtering out an inactive category hides its whole branch.
+func CategoryTree(categories []models.Category, counts map[int64]int64) []*CategoryNode {
+	nodes := map[int64]*CategoryNode{}
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryNode{Category: category, ProductCount: counts[category.ID], Children: []*CategoryNode{}}
+	}
+
+	roots := []*CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID == nil {
+			roots = append(roots, node)
+		} else if parent, ok := nodes[*category.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	var total func(node *CategoryNode) int64
+	total = func(node *CategoryNode) int64 {
+		node.TotalProductCount = node.ProductCount
+		for _, child := range node.Children {
+			node.TotalProductCount += total(child)
+		}
+		return node.TotalProductCount
+	}
+	for _, root := range roots {
+		total(root)
+	}
+
+	return roots
+}
+
+// ProductCountsByCategory counts the products in each category, trashed
+// products excluded.
+func ProductCountsByCategory() (map[int64]int64, error) {
+	var rows []struct {
+		CategoryID int64
+		Total      int64
+	}
+	if err := facades.Orm().Query().Raw(`SELECT category_id, COUNT(*) AS total FROM products
+		WHERE deleted_at IS NULL GROUP BY category_id`).Scan(&rows); err != nil {
+		return nil, err
+	}
+
+	counts := map[int64]int64{}
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Total
+	}
+
+	return counts, nil
+}
+
+// CategoryDescendantIDs returns the category and every category below it.
+func CategoryDescendantIDs(categoryID int64) ([]int64, error) {
+	var rows []struct {
+		ID int64
+	}
+	if err := facades.Orm().Query().Raw(`WITH RECURSIVE branch AS (
+			SELECT id FROM categories WHERE id = ?
+			UNION
+			SELECT categories.id FROM categories JOIN branch ON categories.parent_id = branch.id
+		) SELECT id FROM branch`, categoryID).Scan(&rows); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+
+	return ids, nil
+}
+
+// ValidateCategoryParent checks that parentID exists and is not the category
+// itself or one of its descendants. Pass categoryID 0 for a new category.
+func ValidateCategoryParent(categoryID, parentID int64) error {
+	var parent models.Category
+	if err := facades.Orm().Query().Where("id = ?", parentID).First(&parent); err != nil {
+		return err
+	}
+	if parent.ID == 0 {
+		return ErrCategoryParentNotFound
+	}
+	if categoryID == 0 {
+		return nil
+	}
+
+	descendants, err := CategoryDescendantIDs(categoryID)
+	if err != nil {
+		return err
+	}
+	for _, id := range descendants {
+		if id == parentID {
+			return ErrCategoryCycle
+		}
+	}
+
+	return nil
+}
+
+// hiddenCategoryIDs selects inactive categories and everything below them.
+const hiddenCategoryIDs = `WITH RECURSIVE hidden AS (
+		SELECT id FROM categories WHERE status = false
+		UNION
+		SELECT categories.id FROM categories JOIN hidden ON categories.parent_id = hidden.id
+	) SELECT id FROM hidden`
+
+// InActiveCategory is a query scope that keeps only products whose category
+// and all its parents are active.
+func InActiveCategory(query orm.Query) orm.Query {
+	return query.Where("COALESCE(products.category_id, 0) NOT IN (" + hiddenCategoryIDs + ")")
+}
+
+// HiddenCategoryProductIDs returns which of the given products sit in an
+// inactive category or below one.
+func HiddenCategoryProductIDs(productIDs []int64) (map[int64]bool, error) {
+	hidden := map[int64]bool{}
+	if len(productIDs) == 0 {
+		return hidden, nil
+	}
+
+	var rows []struct {
+		ID int64
+	}
+	if err := facades.Orm().Query().Raw("SELECT products.id FROM products WHERE products.id IN ? AND products.category_id IN ("+hiddenCategoryIDs+")", productIDs).Scan(&rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		hidden[row.ID] = true
+	}
+
+	return hidden, nil
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+var (
+	ErrComboNotFound      = errors.New("combo not found")
+	ErrComboChoiceInvalid = errors.New("invalid combo choice")
+)
+
+// ActiveCombo loads a combo that is on sale with its slots, options and the
+// products behind them.
+func ActiveCombo(comboID int64) (models.Combos, error) {
+	var combo models.Combos
+	if err := facades.Orm().Query().Where("id = ? AND status = ?", comboID, true).
+		With("Slots", func(query orm.Query) orm.Query {
+			return query.OrderBy("sort_order").OrderBy("id")
+		}).
+		With("Slots.Options.Product").
+		With("Slots.Options.Variant").
+		First(&combo); err != nil {
+		return combo, err
+	}
+	if combo.ID == 0 {
+		return combo, ErrComboNotFound
+	}
+
+	return combo, nil
+}
+
+// ResolveComboChoices picks one option per slot of the combo. choices maps a
+// slot id to the chosen option id; slots with a single option may be left out.
+// The returned options are loaded with their Slot, Product and Variant.
+func ResolveComboChoices(combo models.Combos, choices map[int64]int64) ([]models.ComboSlotOptions, error) {
+	slotIDs := map[int64]bool{}
+	for _, slot := range combo.Slots {
+		slotIDs[slot.ID] = true
+	}
+	for slotID := range choices {
+		if !slotIDs[slotID] {
+			return nil, fmt.Errorf("%w: slot %d is not part of this combo", ErrComboChoiceInvalid, slotID)
+		}
+	}
+
+	selected := make([]models.ComboSlotOptions, 0, len(combo.Slots))
+	for index := range combo.Slots {
+		slot := &combo.Slots[index]
+		optionID, chosen := choices[slot.ID]
+		if !chosen {
+			if len(slot.Options) != 1 {
+				return nil, fmt.Errorf("%w: choose an item for %s", ErrComboChoiceInvalid, slot.Name)
+			}
+			optionID = slot.Options[0].ID
+		}
+
+		var option *models.ComboSlotOptions
+		for optionIndex := range slot.Options {
+			if slot.Options[optionIndex].ID == optionID {
+				option = &slot.Options[optionIndex]
+				break
+			}
+		}
+		if option == nil {
+			return nil, fmt.Errorf("%w: option %d is not available for %s", ErrComboChoiceInvalid, optionID, slot.Name)
+		}
+		if option.Product == nil || option.Product.ID == 0 || !option.Product.Status {
+			return nil, fmt.Errorf("%w: %s is not available", ErrComboChoiceInvalid, slot.Name)
+		}
+		if option.VariantID != nil && (option.Variant == nil || !option.Variant.Status) {
+			return nil, fmt.Errorf("%w: %s is not available", ErrComboChoiceInvalid, slot.Name)
+		}
+
+		option.Slot = slot
+		selected = append(selected, *option)
+	}
+
+	return selected, nil
+}
+
+// SameComboChoices reports whether a cart combo was added with exactly the
+// given options, so repeated adds share one line.
+func SameComboChoices(cartCombo models.CartCombos, options []models.ComboSlotOptions) bool {
+	if len(cartCombo.Items) != len(options) {
+		return false
+	}
+	chosen := map[int64]bool{}
+	for _, item := range cartCombo.Items {
+		if item.ComboSlotOptionID != nil {
+			chosen[*item.ComboSlotOptionID] = true
+		}
+	}
+	for _, option := range options {
+		if !chosen[option.ID] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CartComboUnitPrice is the price of one combo in a cart: the bundle price
+// plus the surcharges of premium choices.
+func CartComboUnitPrice(cartCombo models.CartCombos) float64 {
+	price := cartCombo.Combo.Price
+	for _, item := range cartCombo.Items {
+		if item.ComboSlotOption != nil {
+			price += item.ComboSlotOption.ExtraPrice
+		}
+	}
+
+	return price
+}
+
+// LoadCartCombos returns the combos of a cart with their component lines and
+// unit prices filled in.
+func LoadCartCombos(cartID int64) ([]models.CartCombos, error) {
+	cartCombos := []models.CartCombos{}
+	if err := facades.Orm().Query().Where("cart_id = ?", cartID).
+		With("Combo").
+		With("Items.Product").
+		With("Items.Variant").
+		With("Items.ComboSlotOption").
+		Find(&cartCombos); err != nil {
+		return nil, err
+	}
+	for index := range cartCombos {
+		cartCombos[index].UnitPrice = CartComboUnitPrice(cartCombos[index])
+	}
+
+	return cartCombos, nil
+}
+
+// AllocateComboPrice spreads the charged price of a cart combo over its
+// component lines in proportion to their menu prices, so revenue and margin
+// reports see what each product actually earned. It returns the unit price of
+// each line, in the order of cartCombo.Items.
+func AllocateComboPrice(cartCombo models.CartCombos) []float64 {
+	charged := cartCombo.UnitPrice * float64(cartCombo.Quantity)
+	weights := make([]float64, len(cartCombo.Items))
+	var totalWeight float64
+	var totalQuantity int
+	for index, item := range cartCombo.Items {
+		listPrice := item.Product.Price
+		if item.Variant != nil && item.Variant.ID != 0 {
+			listPrice = item.Variant.Price
+		}
+		weights[index] = listPrice * float64(item.Quantity)
+		totalWeight += weights[index]
+		totalQuantity += item.Quantity
+	}
+
+	unitPrices := make([]float64, len(cartCombo.Items))
+	for index, item := range cartCombo.Items {
+		if item.Quantity == 0 {
+			continue
+		}
+		share := float64(item.Quantity) / float64(totalQuantity)
+		if totalWeight > 0 {
+			share = weights[index] / totalWeight
+		}
+		unitPrices[index] = charged * share / float64(item.Quantity)
+	}
+
+	return unitPrices
+}
+
+// DeleteCartCombos removes the cart combos matching the condition together
+// with their component lines.
+func DeleteCartCombos(tx orm.Query, query string, args ...any) error {
+	var cartCombos []models.CartCombos
+	if err := tx.Where(query, args...).Find(&cartCombos); err != nil {
+		return err
+	}
+	for _, cartCombo := range cartCombos {
+		if err := DeleteCartItems(tx, "cart_combo_id = ?", cartCombo.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Model(&models.CartCombos{}).Where("id = ?", cartCombo.ID).Delete(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"goravel/app/models"
+)
+
+type CombosTestSuite struct {
+	suite.Suite
+	combo models.Combos
+}
+
+func TestCombosTestSuite(t *testing.T) {
+	suite.Run(t, new(CombosTestSuite))
+}
+
+// SetupTest will run before each test in the suite.
+func (s *CombosTestSuite) SetupTest() {
+	largeID := int64(31)
+	s.combo = models.Combos{ID: 1, Name: "Lunch", Price: 99000, Status: true, Slots: []models.ComboSlots{
+		{ID: 1, Name: "Main", Quantity: 1, Options: []models.ComboSlotOptions{
+			{ID: 11, SlotID: 1, ProductID: 1, Product: &models.Product{ID: 1, Price: 60000, Status: true}},
+			{ID: 12, SlotID: 1, ProductID: 2, Product: &models.Product{ID: 2, Price: 65000, Status: true}, ExtraPrice: 10000},
+		}},
+		{ID: 2, Name: "Drink", Quantity: 1, Options: []models.ComboSlotOptions{
+			{ID: 21, SlotID: 2, ProductID: 3, Product: &models.Product{ID: 3, Price: 25000, Status: true},
+				VariantID: &largeID, Variant: &models.ProductVariants{ID: largeID, Price: 30000, Status: true}},
+		}},
+	}}
+}
+
+func (s *CombosTestSuite) TestSingleOptionSlotIsPickedForTheCustomer() {
+	options, err := ResolveComboChoices(s.combo, map[int64]int64{1: 12})
+
+	s.NoError(err)
+	s.Len(options, 2)
+	s.Equal(int64(12), options[0].ID)
+	s.Equal("Main", options[0].Slot.Name)
+	s.Equal(int64(21), options[1].ID)
+	s.Equal("Drink", options[1].Slot.Name)
+}
+
+func (s *CombosTestSuite) TestSlotWithSeveralOptionsMustBeChosen() {
+	_, err := ResolveComboChoices(s.combo, map[int64]int64{})
+
+	s.ErrorIs(err, ErrComboChoiceInvalid)
+}
+
+func (s *CombosTestSuite) TestSlotOfAnotherComboIsRejected() {
+	_, err := ResolveComboChoices(s.combo, map[int64]int64{1: 11, 9: 91})
+
+	s.ErrorIs(err, ErrComboChoiceInvalid)
+}
+
+func (s *CombosTestSuite) TestOptionOfAnotherSlotIsRejected() {
+	_, err := ResolveComboChoices(s.combo, map[int64]int64{1: 21})
+
+	s.ErrorIs(err, ErrComboChoiceInvalid)
+}
+
+func (s *CombosTestSuite) TestUnavailableProductIsRejected() {
+	s.combo.Slots[0].Options[0].Product.Status = false
+	_, err := ResolveComboChoices(s.combo, map[int64]int64{1: 11})
+	s.ErrorIs(err, ErrComboChoiceInvalid)
+
+	// A deleted product leaves the option without one.
+	s.combo.Slots[0].Options[0].Product = &models.Product{}
+	_, err = ResolveComboChoices(s.combo, map[int64]int64{1: 11})
+	s.ErrorIs(err, ErrComboChoiceInvalid)
+}
+
+func (s *CombosTestSuite) TestUnavailableVariantIsRejected() {
+	s.combo.Slots[1].Options[0].Variant.Status = false
+	_, err := ResolveComboChoices(s.combo, map[int64]int64{1: 11})
+	s.ErrorIs(err, ErrComboChoiceInvalid)
+
+	s.combo.Slots[1].Options[0].Variant = nil
+	_, err = ResolveComboChoices(s.combo, map[int64]int64{1: 11})
+	s.ErrorIs(err, ErrComboChoiceInvalid)
+}
+
+func (s *CombosTestSuite) TestCartComboUnitPriceAddsExtras() {
+	options, err := ResolveComboChoices(s.combo, map[int64]int64{1: 12})
+	s.NoError(err)
+
+	cartCombo := models.CartCombos{Combo: s.combo, Quantity: 1}
+	for index := range options {
+		cartCombo.Items = append(cartCombo.Items, models.CartItem{ComboSlotOptionID: &options[index].ID, ComboSlotOption: &options[index]})
+	}
+
+	s.Equal(109000.0, CartComboUnitPrice(cartCombo))
+	s.True(SameComboChoices(cartCombo, []models.ComboSlotOptions{{ID: 21}, {ID: 12}}))
+	s.False(SameComboChoices(cartCombo, []models.ComboSlotOptions{{ID: 11}, {ID: 21}}))
+}
+
+func (s *CombosTestSuite) TestAllocateComboPriceFollowsListPrices() {
+	cartCombo := models.CartCombos{UnitPrice: 100000, Quantity: 2, Items: []models.CartItem{
+		{Quantity: 2, Product: models.Product{Price: 60000}},
+		{Quantity: 2, Product: models.Product{Price: 40000}, Variant: &models.ProductVariants{ID: 5, Price: 20000}},
+	}}
+
+	unitPrices := AllocateComboPrice(cartCombo)
+
+	s.InDelta(75000, unitPrices[0], 1e-6)
+	s.InDelta(25000, unitPrices[1], 1e-6)
+	s.InDelta(200000, unitPrices[0]*2+unitPrices[1]*2, 1e-6)
+}
+
+func (s *CombosTestSuite) TestAllocateComboPriceSplitsByQuantityWithoutListPrices() {
+	cartCombo := models.CartCombos{UnitPrice: 90000, Quantity: 1, Items: []models.CartItem{
+		{Quantity: 2},
+		{Quantity: 1},
+		{Quantity: 0},
+	}}
+
+	unitPrices := AllocateComboPrice(cartCombo)
+
+	s.InDelta(30000, unitPrices[0], 1e-6)
+	s.InDelta(30000, unitPrices[1], 1e-6)
+	s.Zero(unitPrices[2])
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"github.com/goravel/framework/facades"
+)
+
+// ProductFoodCosts returns the cost of one portion of each product that has a
+// recipe, valued at the ingredients' weighted average purchase cost.
+func ProductFoodCosts() (map[int64]float64, error) {
+	type productCost struct {
+		ProductID int64
+		FoodCost  float64
+	}
+
+	var rows []productCost
+	if err := facades.Orm().Query().Raw(`SELECT product_ingredients.product_id, SUM(product_ingredients.amount_used * ingredients.unit_cost) AS food_cost
+		FROM product_ingredients
+		JOIN ingredients ON ingredients.id = product_ingredients.ingredient_id
+		GROUP BY product_ingredients.product_id`).Scan(&rows); err != nil {
+		return nil, err
+	}
+
+	costs := make(map[int64]float64, len(rows))
+	for _, row := range rows {
+		costs[row.ProductID] = row.FoodCost
+	}
+
+	return costs, nil
+}
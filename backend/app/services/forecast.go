@@ -0,0 +1,253 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+const NotificationReorder = "reorder_suggestion"
+
+// forecastDecay is the weight of each older week relative to the next newer
+// one, so recent trading counts more than a quiet month ago.
+const forecastDecay = 0.8
+
+// forecastServiceLevel is the z-score used for safety stock (about 95%).
+const forecastServiceLevel = 1.65
+
+// forecastStatuses are the order statuses that actually consumed stock.
+var forecastStatuses = []string{"confirmed", "preparing", "delivering", "completed"}
+
+// ProductForecast is the expected sales of a product in recipe portions, so a
+// large size with a recipe factor of 1.5 counts as one and a half.
+type ProductForecast struct {
+	ProductID int64   `json:"product_id"`
+	Name      string  `json:"name"`
+	Portions  float64 `json:"portions"`
+}
+
+type IngredientForecast struct {
+	IngredientID      int64     `json:"ingredient_id"`
+	Name              string    `json:"name"`
+	Unit              string    `json:"unit"`
+	Quantity          float64   `json:"quantity"`
+	Threshold         float64   `json:"threshold"`
+	ExpectedUsage     float64   `json:"expected_usage"`
+	DailyUsage        []float64 `json:"daily_usage"`
+	SafetyStock       float64   `json:"safety_stock"`
+	ProjectedQuantity float64   `json:"projected_quantity"`
+	DaysOfCover       *float64  `json:"days_of_cover"`
+	ReorderQuantity   float64   `json:"reorder_quantity"`
+	ReorderCost       float64   `json:"reorder_cost"`
+}
+
+type DemandForecast struct {
+	From         string               `json:"from"`
+	Days         int                  `json:"days"`
+	HistoryWeeks int                  `json:"history_weeks"`
+	GeneratedAt  time.Time            `json:"generated_at"`
+	Products     []ProductForecast    `json:"products"`
+	Ingredients  []IngredientForecast `json:"ingredients"`
+}
+
+// ForecastDemand projects ingredient usage for the next days from the last
+// weeks of sales. Each product's expected sales for a weekday and hour is the
+// recency weighted average of what it sold in that slot in past weeks; the
+// rest of today only counts the hours still ahead. Products are multiplied
+// through their recipes and the result is compared to stock to suggest how
+// much to reorder.
+func ForecastDemand(days, weeks int) (DemandForecast, error) {
+	location := RestaurantLocation()
+	now := time.Now().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	since := today.AddDate(0, 0, -7*weeks)
+
+	forecast := DemandForecast{
+		From:         today.Format("2006-01-02"),
+		Days:         days,
+		HistoryWeeks: weeks,
+		GeneratedAt:  now,
+		Products:     []ProductForecast{},
+		Ingredients:  []IngredientForecast{},
+	}
+
+	type saleRow struct {
+		ProductID int64
+		Portions  float64
+		CreatedAt time.Time
+	}
+	var sales []saleRow
+	if err := facades.Orm().Query().Raw(`SELECT order_items.product_id, order_items.quantity * order_items.recipe_factor AS portions, orders.created_at
+		FROM order_items
+		JOIN orders ON orders.id = order_items.order_id
+		WHERE orders.status IN ? AND orders.created_at >= ? AND orders.created_at < ?`, forecastStatuses, since, today).Scan(&sales); err != nil {
+		return forecast, err
+	}
+
+	var recipes []models.ProductIngredient
+	if err := facades.Orm().Query().Find(&recipes); err != nil {
+		return forecast, err
+	}
+	recipeByProduct := map[int64][]models.ProductIngredient{}
+	for _, recipe := range recipes {
+		recipeByProduct[recipe.ProductID] = append(recipeByProduct[recipe.ProductID], recipe)
+	}
+
+	// Only weeks since the first sale count, a new shop is not averaged down
+	// by weeks it was not trading
+	historyWeeks := 0
+	for _, sale := range sales {
+		age := int(today.Sub(sale.CreatedAt.In(location)).Hours() / (24 * 7))
+		if age+1 > historyWeeks {
+			historyWeeks = age + 1
+		}
+	}
+	if historyWeeks > weeks {
+		historyWeeks = weeks
+	}
+
+	var weightSum float64
+	weights := make([]float64, historyWeeks)
+	for age := range weights {
+		weights[age] = math.Pow(forecastDecay, float64(age))
+		weightSum += weights[age]
+	}
+
+	// slots[product][weekday*24+hour] is the weighted average quantity sold
+	slots := map[int64]*[7 * 24]float64{}
+	historyDays := historyWeeks * 7
+	dailyIngredientUsage := map[int64][]float64{}
+	for _, sale := range sales {
+		createdAt := sale.CreatedAt.In(location)
+		age := int(today.Sub(createdAt).Hours() / (24 * 7))
+		if age >= historyWeeks {
+			continue
+		}
+
+		if slots[sale.ProductID] == nil {
+			slots[sale.ProductID] = &[7 * 24]float64{}
+		}
+		slots[sale.ProductID][int(createdAt.Weekday())*24+createdAt.Hour()] += sale.Portions * weights[age] / weightSum
+
+		dayIndex := int(today.Sub(createdAt).Hours() / 24)
+		if dayIndex >= historyDays {
+			dayIndex = historyDays - 1
+		}
+		for _, recipe := range recipeByProduct[sale.ProductID] {
+			if dailyIngredientUsage[recipe.IngredientID] == nil {
+				dailyIngredientUsage[recipe.IngredientID] = make([]float64, historyDays)
+			}
+			dailyIngredientUsage[recipe.IngredientID][dayIndex] += recipe.AmountUsed * sale.Portions
+		}
+	}
+
+	productDaily := map[int64][]float64{}
+	for productID, productSlots := range slots {
+		daily := make([]float64, days)
+		for day := 0; day < days; day++ {
+			weekday := int(today.AddDate(0, 0, day).Weekday())
+			for hour := 0; hour < 24; hour++ {
+				if day == 0 && hour < now.Hour() {
+					continue
+				}
+				daily[day] += productSlots[weekday*24+hour]
+			}
+		}
+		productDaily[productID] = daily
+	}
+
+	var products []models.Product
+	if err := facades.Orm().Query().Find(&products); err != nil {
+		return forecast, err
+	}
+	for _, product := range products {
+		var total float64
+		for _, quantity := range productDaily[product.ID] {
+			total += quantity
+		}
+		if total > 0 {
+			forecast.Products = append(forecast.Products, ProductForecast{ProductID: product.ID, Name: product.Name, Portions: roundQuantity(total)})
+		}
+	}
+	sort.Slice(forecast.Products, func(i, j int) bool { return forecast.Products[i].Portions > forecast.Products[j].Portions })
+
+	var ingredients []models.Ingredients
+	if err := facades.Orm().Query().OrderBy("name").Find(&ingredients); err != nil {
+		return forecast, err
+	}
+
+	ingredientDaily := map[int64][]float64{}
+	for productID, daily := range productDaily {
+		for _, recipe := range recipeByProduct[productID] {
+			if ingredientDaily[recipe.IngredientID] == nil {
+				ingredientDaily[recipe.IngredientID] = make([]float64, days)
+			}
+			for day, quantity := range daily {
+				ingredientDaily[recipe.IngredientID][day] += quantity * recipe.AmountUsed
+			}
+		}
+	}
+
+	for _, ingredient := range ingredients {
+		item := IngredientForecast{
+			IngredientID: ingredient.ID,
+			Name:         ingredient.Name,
+			Unit:         ingredient.Unit,
+			Quantity:     ingredient.Quantity,
+			Threshold:    ingredient.Threshold,
+			DailyUsage:   make([]float64, days),
+		}
+		for day, quantity := range ingredientDaily[ingredient.ID] {
+			item.DailyUsage[day] = roundQuantity(quantity)
+			item.ExpectedUsage += quantity
+		}
+
+		item.SafetyStock = forecastServiceLevel * standardDeviation(dailyIngredientUsage[ingredient.ID]) * math.Sqrt(float64(days))
+		item.ProjectedQuantity = ingredient.Quantity - item.ExpectedUsage
+		if item.ExpectedUsage > 0 {
+			cover := ingredient.Quantity / (item.ExpectedUsage / float64(days))
+			cover = math.Round(cover*10) / 10
+			item.DaysOfCover = &cover
+		}
+
+		target := item.ExpectedUsage + item.SafetyStock + ingredient.Threshold
+		if target > ingredient.Quantity && item.ExpectedUsage > 0 {
+			item.ReorderQuantity = math.Ceil((target-ingredient.Quantity)*1000) / 1000
+			item.ReorderCost = item.ReorderQuantity * ingredient.UnitCost
+		}
+
+		item.ExpectedUsage = roundQuantity(item.ExpectedUsage)
+		item.SafetyStock = roundQuantity(item.SafetyStock)
+		item.ProjectedQuantity = roundQuantity(item.ProjectedQuantity)
+		forecast.Ingredients = append(forecast.Ingredients, item)
+	}
+
+	return forecast, nil
+}
+
+func standardDeviation(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	var mean float64
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	var variance float64
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+
+	return math.Sqrt(variance / float64(len(values)-1))
+}
+
+func roundQuantity(quantity float64) float64 {
+	return math.Round(quantity*1000) / 1000
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+const NotificationLowStock = "low_stock"
+
+// CheckLowStock raises one notification per ingredient that fell below its
+// threshold. An ingredient keeps a single open notification until its stock
+// climbs back to the threshold, at which point the notification is resolved
+// and the next breach raises a new one.
+func CheckLowStock() ([]models.AdminNotifications, error) {
+	var ingredients []models.Ingredients
+	if err := facades.Orm().Query().Find(&ingredients); err != nil {
+		return nil, err
+	}
+
+	var open []models.AdminNotifications
+	if err := facades.Orm().Query().Where("type = ? AND resolved_at IS NULL", NotificationLowStock).Find(&open); err != nil {
+		return nil, err
+	}
+	openByIngredient := map[int64]models.AdminNotifications{}
+	for _, notification := range open {
+		if notification.IngredientID != nil {
+			openByIngredient[*notification.IngredientID] = notification
+		}
+	}
+
+	now := time.Now()
+	var raised []models.AdminNotifications
+	for _, ingredient := range ingredients {
+		notification, isOpen := openByIngredient[ingredient.ID]
+		below := ingredient.Quantity < ingredient.Threshold
+
+		if !below && isOpen {
+			if _, err := facades.Orm().Query().Model(&models.AdminNotifications{}).Where("id = ?", notification.ID).Update("resolved_at", now); err != nil {
+				return raised, err
+			}
+			continue
+		}
+		if !below || isOpen {
+			continue
+		}
+
+		ingredientID := ingredient.ID
+		notification = models.AdminNotifications{
+			Type:         NotificationLowStock,
+			Title:        fmt.Sprintf("Low stock: %s", ingredient.Name),
+			Message:      fmt.Sprintf("%s is at %g %s, below the threshold of %g %s.", ingredient.Name, ingredient.Quantity, ingredient.Unit, ingredient.Threshold, ingredient.Unit),
+			IngredientID: &ingredientID,
+		}
+		if err := facades.Orm().Query().Create(&notification); err != nil {
+			return raised, err
+		}
+		raised = append(raised, notification)
+	}
+
+	return raised, nil
+}
@@ -0,0 +1,430 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+// MenuColumns is the layout of menu exports and imports, one row per variant.
+// Products without variants take a single row with the variant columns empty.
+var MenuColumns = []string{
+	"category", "name", "description", "price", "thumbnail", "status",
+	"variant_name", "variant_sku", "variant_price", "variant_recipe_factor", "variant_is_default", "variant_status",
+}
+
+var requiredMenuColumns = []string{"category", "name", "price"}
+
+// MenuImportError points at the spreadsheet line (header is line 1) that
+// could not be imported.
+type MenuImportError struct {
+	Line    int    `json:"line"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// MenuImportResult summarises what an import did, or would do on a dry run.
+type MenuImportResult struct {
+	DryRun            bool              `json:"dry_run"`
+	Rows              int               `json:"rows"`
+	CategoriesCreated []string          `json:"categories_created"`
+	ProductsCreated   int               `json:"products_created"`
+	ProductsUpdated   int               `json:"products_updated"`
+	VariantsCreated   int               `json:"variants_created"`
+	VariantsUpdated   int               `json:"variants_updated"`
+	Errors            []MenuImportError `json:"errors"`
+}
+
+type menuImportVariant struct {
+	line     int
+	existing *models.ProductVariants
+	variant  models.ProductVariants
+}
+
+type menuImportProduct struct {
+	line     int
+	existing *models.Product
+	category string
+	product  models.Product
+	variants []*menuImportVariant
+}
+
+// MenuImportPlan is the validated import, grouped by product in file order.
+type MenuImportPlan struct {
+	categories map[string]int64
+	newCats    []string
+	products   []*menuImportProduct
+}
+
+func parseImportBool(value string, fallback bool) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return fallback, true
+	case "1", "true", "yes", "y", "active", "on":
+		return true, true
+	case "0", "false", "no", "n", "inactive", "off":
+		return false, true
+	}
+
+	return false, false
+}
+
+func parseImportNumber(value string, fallback float64) (float64, bool) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+	if value == "" {
+		return fallback, true
+	}
+	number, err := strconv.ParseFloat(value, 64)
+
+	return number, err == nil
+}
+
+// PlanMenuImport validates every row against the current catalog and returns
+// the plan together with a summary. Nothing is written.
+func PlanMenuImport(rows [][]string) (*MenuImportPlan, MenuImportResult, error) {
+	result := MenuImportResult{CategoriesCreated: []string{}, Errors: []MenuImportError{}}
+	if len(rows) == 0 {
+		result.Errors = append(result.Errors, MenuImportError{Line: 1, Message: "file is empty"})
+		return nil, result, nil
+	}
+
+	columns := map[string]int{}
+	for index, header := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = index
+	}
+	for _, column := range requiredMenuColumns {
+		if _, ok := columns[column]; !ok {
+			result.Errors = append(result.Errors, MenuImportError{Line: 1, Column: column, Message: "column is missing"})
+		}
+	}
+	if len(result.Errors) > 0 {
+		return nil, result, nil
+	}
+
+	var categories []models.Category
+	if err := facades.Orm().Query().Find(&categories); err != nil {
+		return nil, result, err
+	}
+	var products []models.Product
+	if err := facades.Orm().Query().WithTrashed().Find(&products); err != nil {
+		return nil, result, err
+	}
+	var variants []models.ProductVariants
+	if err := facades.Orm().Query().Find(&variants); err != nil {
+		return nil, result, err
+	}
+
+	plan := &MenuImportPlan{categories: map[string]int64{}}
+	for _, category := range categories {
+		plan.categories[strings.ToLower(strings.TrimSpace(category.Name))] = category.ID
+	}
+	productsByID := map[int64]*models.Product{}
+	productsByName := map[string]*models.Product{}
+	for i := range products {
+		productsByID[products[i].ID] = &products[i]
+		if products[i].DeletedAt.Valid {
+			continue
+		}
+		productsByName[strings.ToLower(strings.TrimSpace(products[i].Name))] = &products[i]
+	}
+	variantsBySKU := map[string]*models.ProductVariants{}
+	for i := range variants {
+		variantsBySKU[strings.ToLower(variants[i].SKU)] = &variants[i]
+	}
+
+	planned := map[string]*menuImportProduct{}
+	plannedByID := map[int64]*menuImportProduct{}
+	skuLines := map[string]int{}
+	newCategories := map[string]bool{}
+
+	for index, row := range rows[1:] {
+		line := index + 2
+		cell := func(column string) string {
+			if position, ok := columns[column]; ok && position < len(row) {
+				return strings.TrimSpace(row[position])
+			}
+			return ""
+		}
+		isBlank := true
+		for _, value := range row {
+			if strings.TrimSpace(value) != "" {
+				isBlank = false
+				break
+			}
+		}
+		if isBlank {
+			continue
+		}
+		result.Rows++
+
+		rowErrors := []MenuImportError{}
+		fail := func(column, message string) {
+			rowErrors = append(rowErrors, MenuImportError{Line: line, Column: column, Message: message})
+		}
+
+		name := cell("name")
+		if name == "" {
+			fail("name", "name is required")
+		}
+		categoryName := cell("category")
+		if categoryName == "" {
+			fail("category", "category is required")
+		}
+		price, ok := parseImportNumber(cell("price"), -1)
+		if !ok || price < 0 {
+			fail("price", "price must be a number of at least 0")
+		}
+		status, ok := parseImportBool(cell("status"), true)
+		if !ok {
+			fail("status", "status must be true or false")
+		}
+
+		variantName, sku := cell("variant_name"), cell("variant_sku")
+		hasVariant := variantName != "" || sku != "" || cell("variant_price") != ""
+		var variant models.ProductVariants
+		if hasVariant {
+			if variantName == "" {
+				fail("variant_name", "variant_name is required when a variant is given")
+			}
+			if sku == "" {
+				fail("variant_sku", "variant_sku is required when a variant is given")
+			} else if previous, ok := skuLines[strings.ToLower(sku)]; ok {
+				fail("variant_sku", fmt.Sprintf("sku %s is already used on line %d", sku, previous))
+			} else {
+				skuLines[strings.ToLower(sku)] = line
+			}
+			variantPrice, ok := parseImportNumber(cell("variant_price"), price)
+			if !ok || variantPrice < 0 {
+				fail("variant_price", "variant_price must be a number of at least 0")
+			}
+			recipeFactor, ok := parseImportNumber(cell("variant_recipe_factor"), 1)
+			if !ok || recipeFactor <= 0 {
+				fail("variant_recipe_factor", "variant_recipe_factor must be greater than 0")
+			}
+			isDefault, ok := parseImportBool(cell("variant_is_default"), false)
+			if !ok {
+				fail("variant_is_default", "variant_is_default must be true or false")
+			}
+			variantStatus, ok := parseImportBool(cell("variant_status"), true)
+			if !ok {
+				fail("variant_status", "variant_status must be true or false")
+			}
+			variant = models.ProductVariants{
+				Name:         variantName,
+				SKU:          sku,
+				Price:        variantPrice,
+				RecipeFactor: recipeFactor,
+				IsDefault:    isDefault,
+				Status:       variantStatus,
+			}
+		}
+
+		// A known SKU decides which product the row belongs to, otherwise the product name does
+		var existingVariant *models.ProductVariants
+		var existingProduct *models.Product
+		if sku != "" {
+			existingVariant = variantsBySKU[strings.ToLower(sku)]
+		}
+		if existingVariant != nil {
+			existingProduct = productsByID[existingVariant.ProductID]
+			if existingProduct != nil && existingProduct.DeletedAt.Valid {
+				fail("variant_sku", fmt.Sprintf("sku %s belongs to a deleted product", sku))
+			}
+		} else if name != "" {
+			existingProduct = productsByName[strings.ToLower(name)]
+		}
+
+		if len(rowErrors) > 0 {
+			result.Errors = append(result.Errors, rowErrors...)
+			continue
+		}
+
+		var entry *menuImportProduct
+		if existingProduct != nil {
+			entry = plannedByID[existingProduct.ID]
+		} else {
+			entry = planned[strings.ToLower(name)]
+		}
+		if entry == nil {
+			entry = &menuImportProduct{
+				line:     line,
+				existing: existingProduct,
+				category: categoryName,
+				product: models.Product{
+					Name:        name,
+					Description: cell("description"),
+					Price:       price,
+					Thumbnail:   cell("thumbnail"),
+					Status:      status,
+				},
+			}
+			if existingProduct != nil {
+				// Blank optional cells keep what the product already has
+				entry.product.ID = existingProduct.ID
+				if _, ok := columns["description"]; !ok || entry.product.Description == "" {
+					entry.product.Description = existingProduct.Description
+				}
+				if _, ok := columns["thumbnail"]; !ok || entry.product.Thumbnail == "" {
+					entry.product.Thumbnail = existingProduct.Thumbnail
+				}
+				plannedByID[existingProduct.ID] = entry
+			}
+			planned[strings.ToLower(name)] = entry
+			plan.products = append(plan.products, entry)
+
+			categoryKey := strings.ToLower(categoryName)
+			if _, ok := plan.categories[categoryKey]; !ok && !newCategories[categoryKey] {
+				newCategories[categoryKey] = true
+				plan.newCats = append(plan.newCats, categoryName)
+			}
+			if existingProduct != nil {
+				result.ProductsUpdated++
+			} else {
+				result.ProductsCreated++
+			}
+		}
+
+		if hasVariant {
+			if variant.IsDefault {
+				for _, other := range entry.variants {
+					if other.variant.IsDefault {
+						result.Errors = append(result.Errors, MenuImportError{
+							Line:    line,
+							Column:  "variant_is_default",
+							Message: fmt.Sprintf("line %d already sets the default variant of %s", other.line, entry.product.Name),
+						})
+						break
+					}
+				}
+			}
+			entry.variants = append(entry.variants, &menuImportVariant{line: line, existing: existingVariant, variant: variant})
+			if existingVariant != nil {
+				result.VariantsUpdated++
+			} else {
+				result.VariantsCreated++
+			}
+		}
+	}
+
+	result.CategoriesCreated = append(result.CategoriesCreated, plan.newCats...)
+
+	return plan, result, nil
+}
+
+// ApplyMenuImport writes a validated plan in one transaction. Price changes
+// on existing products are recorded in the price history.
+func ApplyMenuImport(plan *MenuImportPlan, changedBy *int64) error {
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return err
+	}
+	if err := applyMenuImport(tx, plan, changedBy); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func applyMenuImport(tx orm.Query, plan *MenuImportPlan, changedBy *int64) error {
+	for _, name := range plan.newCats {
+		category := models.Category{Name: name, Status: true}
+		if err := tx.Create(&category); err != nil {
+			return err
+		}
+		plan.categories[strings.ToLower(name)] = category.ID
+	}
+
+	now := time.Now()
+	for _, entry := range plan.products {
+		entry.product.CategoryID = plan.categories[strings.ToLower(entry.category)]
+
+		if entry.existing == nil {
+			if err := tx.Create(&entry.product); err != nil {
+				return err
+			}
+			if err := RecordPriceChange(tx, entry.product.ID, entry.product.Price, PriceSourceInitial, nil, entry.product.CreatedAt); err != nil {
+				return err
+			}
+		} else {
+			if _, err := tx.Model(&models.Product{}).Where("id = ?", entry.product.ID).Update(map[string]interface{}{
+				"name":        entry.product.Name,
+				"description": entry.product.Description,
+				"price":       entry.product.Price,
+				"thumbnail":   entry.product.Thumbnail,
+				"status":      entry.product.Status,
+				"category_id": entry.product.CategoryID,
+			}); err != nil {
+				return err
+			}
+			if entry.existing.Price != entry.product.Price {
+				if err := RecordPriceChange(tx, entry.product.ID, entry.product.Price, PriceSourceManual, changedBy, now); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, item := range entry.variants {
+			variant := item.variant
+			variant.ProductID = entry.product.ID
+			if item.existing == nil {
+				if err := tx.Create(&variant); err != nil {
+					return err
+				}
+			} else {
+				variant.ID = item.existing.ID
+				if _, err := tx.Model(&models.ProductVariants{}).Where("id = ?", variant.ID).Update(map[string]interface{}{
+					"name":          variant.Name,
+					"price":         variant.Price,
+					"recipe_factor": variant.RecipeFactor,
+					"is_default":    variant.IsDefault,
+					"status":        variant.Status,
+				}); err != nil {
+					return err
+				}
+			}
+			if variant.IsDefault {
+				if _, err := tx.Model(&models.ProductVariants{}).Where("product_id = ? AND id <> ?", variant.ProductID, variant.ID).Update("is_default", false); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// MenuExportRows returns the catalog in the MenuColumns layout, header first.
+func MenuExportRows() ([][]any, error) {
+	var products []models.Product
+	if err := facades.Orm().Query().With("Category").With("Variants", func(query orm.Query) orm.Query {
+		return query.OrderBy("sort_order").OrderBy("id")
+	}).OrderBy("category_id").OrderBy("name").Find(&products); err != nil {
+		return nil, err
+	}
+
+	header := make([]any, len(MenuColumns))
+	for i, column := range MenuColumns {
+		header[i] = column
+	}
+	rows := [][]any{header}
+	for _, product := range products {
+		base := []any{product.Category.Name, product.Name, product.Description, product.Price, product.Thumbnail, product.Status}
+		if len(product.Variants) == 0 {
+			rows = append(rows, append(base, nil, nil, nil, nil, nil, nil))
+			continue
+		}
+		for _, variant := range product.Variants {
+			row := append(append([]any{}, base...), variant.Name, variant.SKU, variant.Price, variant.RecipeFactor, variant.IsDefault, variant.Status)
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
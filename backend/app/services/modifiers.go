@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+var ErrInvalidModifiers = errors.New("invalid modifier selection")
+
+// ModifierSelectionError explains which rule of which group a selection broke.
+type ModifierSelectionError struct {
+	GroupID int64
+	Group   string
+	Reason  string
+}
+
+func (e *ModifierSelectionError) Error() string {
+	if e.Group == "" {
+		return e.Reason
+	}
+
+	return fmt.Sprintf("%s: %s", e.Group, e.Reason)
+}
+
+func (e *ModifierSelectionError) Unwrap() error {
+	return ErrInvalidModifiers
+}
+
+// MinSelections is how many options a customer has to pick from the group.
+func MinSelections(group models.ModifierGroups) int {
+	if group.Required && group.MinSelect < 1 {
+		return 1
+	}
+
+	return group.MinSelect
+}
+
+// ProductModifierGroups returns the modifier groups attached to each product,
+// in display order and with only their active options.
+func ProductModifierGroups(productIDs []int64) (map[int64][]models.ModifierGroups, error) {
+	result := map[int64][]models.ModifierGroups{}
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+
+	ids := make([]any, 0, len(productIDs))
+	for _, id := range productIDs {
+		ids = append(ids, id)
+	}
+
+	var links []models.ProductModifierGroups
+	if err := facades.Orm().Query().WhereIn("product_id", ids).OrderBy("sort_order").OrderBy("id").Find(&links); err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return result, nil
+	}
+
+	groupIDs := make([]any, 0, len(links))
+	for _, link := range links {
+		groupIDs = append(groupIDs, link.ModifierGroupID)
+	}
+
+	var groups []models.ModifierGroups
+	if err := facades.Orm().Query().WhereIn("id", groupIDs).With("Options", func(query orm.Query) orm.Query {
+		return query.Where("status = ?", true).OrderBy("sort_order").OrderBy("id")
+	}).Find(&groups); err != nil {
+		return nil, err
+	}
+	groupsByID := map[int64]models.ModifierGroups{}
+	for _, group := range groups {
+		groupsByID[group.ID] = group
+	}
+
+	for _, link := range links {
+		if group, ok := groupsByID[link.ModifierGroupID]; ok {
+			result[link.ProductID] = append(result[link.ProductID], group)
+		}
+	}
+
+	return result, nil
+}
+
+// ResolveModifiers checks the options chosen for a product against the
+// product's groups and their min/max/required rules. The returned options are
+// loaded with their Group.
+func ResolveModifiers(productID int64, optionIDs []int64) ([]models.ModifierOptions, error) {
+	groupsByProduct, err := ProductModifierGroups([]int64{productID})
+	if err != nil {
+		return nil, err
+	}
+
+	return checkModifierSelection(groupsByProduct[productID], optionIDs)
+}
+
+// checkModifierSelection validates the chosen option IDs against a product's
+// modifier groups and returns the options ordered by ID.
+func checkModifierSelection(groups []models.ModifierGroups, optionIDs []int64) ([]models.ModifierOptions, error) {
+	optionsByID := map[int64]models.ModifierOptions{}
+	for index := range groups {
+		for _, option := range groups[index].Options {
+			option.Group = &groups[index]
+			optionsByID[option.ID] = option
+		}
+	}
+
+	seen := map[int64]bool{}
+	counts := map[int64]int{}
+	selected := make([]models.ModifierOptions, 0, len(optionIDs))
+	for _, id := range optionIDs {
+		option, ok := optionsByID[id]
+		if !ok {
+			return nil, &ModifierSelectionError{Reason: fmt.Sprintf("option %d is not available for this product", id)}
+		}
+		if seen[id] {
+			return nil, &ModifierSelectionError{GroupID: option.GroupID, Group: option.Group.Name, Reason: "an option was chosen twice"}
+		}
+		seen[id] = true
+		counts[option.GroupID]++
+		selected = append(selected, option)
+	}
+
+	for _, group := range groups {
+		count := counts[group.ID]
+		if minimum := MinSelections(group); count < minimum {
+			return nil, &ModifierSelectionError{GroupID: group.ID, Group: group.Name, Reason: fmt.Sprintf("choose at least %d option(s)", minimum)}
+		}
+		if group.MaxSelect > 0 && count > group.MaxSelect {
+			return nil, &ModifierSelectionError{GroupID: group.ID, Group: group.Name, Reason: fmt.Sprintf("choose at most %d option(s)", group.MaxSelect)}
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool { return selected[i].ID < selected[j].ID })
+
+	return selected, nil
+}
+
+// SameModifiers reports whether a cart line was added with exactly the given
+// options, so repeated adds of the same customisation share one line.
+func SameModifiers(item models.CartItem, options []models.ModifierOptions) bool {
+	if len(item.Modifiers) != len(options) {
+		return false
+	}
+	chosen := map[int64]bool{}
+	for _, modifier := range item.Modifiers {
+		chosen[modifier.ModifierOptionID] = true
+	}
+	for _, option := range options {
+		if !chosen[option.ID] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ModifiersTotal is the sum of the price deltas of a cart line's modifiers.
+func ModifiersTotal(item models.CartItem) float64 {
+	var total float64
+	for _, modifier := range item.Modifiers {
+		total += modifier.Option.PriceDelta
+	}
+
+	return total
+}
+
+// DeleteCartItems removes the cart lines matching the condition together with
+// their chosen modifiers.
+func DeleteCartItems(tx orm.Query, query string, args ...any) error {
+	var items []models.CartItem
+	if err := tx.Where(query, args...).Find(&items); err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	itemIDs := make([]any, 0, len(items))
+	for _, item := range items {
+		itemIDs = append(itemIDs, item.ID)
+	}
+	if _, err := tx.Model(&models.CartItemModifiers{}).WhereIn("cart_item_id", itemIDs).Delete(); err != nil {
+		return err
+	}
+	_, err := tx.Model(&models.CartItem{}).WhereIn("id", itemIDs).Delete()
+
+	return err
+}
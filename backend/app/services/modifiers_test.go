@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"goravel/app/models"
+)
+
+type ModifiersTestSuite struct {
+	suite.Suite
+	groups []models.ModifierGroups
+}
+
+func TestModifiersTestSuite(t *testing.T) {
+	suite.Run(t, new(ModifiersTestSuite))
+}
+
+// SetupTest will run before each test in the suite.
+func (s *ModifiersTestSuite) SetupTest() {
+	s.groups = []models.ModifierGroups{
+		{ID: 1, Name: "Size", Required: true, MaxSelect: 1, Options: []models.ModifierOptions{
+			{ID: 11, GroupID: 1, Name: "M"},
+			{ID: 12, GroupID: 1, Name: "L", PriceDelta: 5000},
+		}},
+		{ID: 2, Name: "Topping", MaxSelect: 2, Options: []models.ModifierOptions{
+			{ID: 21, GroupID: 2, Name: "Pearl"},
+			{ID: 22, GroupID: 2, Name: "Jelly"},
+			{ID: 23, GroupID: 2, Name: "Pudding"},
+		}},
+	}
+}
+
+func (s *ModifiersTestSuite) TestMinSelections() {
+	s.Equal(1, MinSelections(models.ModifierGroups{Required: true}))
+	s.Equal(2, MinSelections(models.ModifierGroups{Required: true, MinSelect: 2}))
+	s.Equal(0, MinSelections(models.ModifierGroups{}))
+}
+
+func (s *ModifiersTestSuite) TestValidSelectionIsSortedAndCarriesGroup() {
+	options, err := checkModifierSelection(s.groups, []int64{22, 12})
+
+	s.NoError(err)
+	s.Len(options, 2)
+	s.Equal(int64(12), options[0].ID)
+	s.Equal("Size", options[0].Group.Name)
+	s.Equal(int64(22), options[1].ID)
+	s.Equal("Topping", options[1].Group.Name)
+}
+
+func (s *ModifiersTestSuite) TestRequiredGroupMustBeChosen() {
+	_, err := checkModifierSelection(s.groups, []int64{21})
+
+	s.assertSelectionError(err, 1)
+}
+
+func (s *ModifiersTestSuite) TestMaxSelectIsEnforced() {
+	_, err := checkModifierSelection(s.groups, []int64{11, 21, 22, 23})
+	s.assertSelectionError(err, 2)
+
+	_, err = checkModifierSelection(s.groups, []int64{11, 12})
+	s.assertSelectionError(err, 1)
+}
+
+func (s *ModifiersTestSuite) TestMinSelectIsEnforced() {
+	s.groups[1].MinSelect = 1
+
+	_, err := checkModifierSelection(s.groups, []int64{11})
+
+	s.assertSelectionError(err, 2)
+}
+
+func (s *ModifiersTestSuite) TestOptionChosenTwiceIsRejected() {
+	_, err := checkModifierSelection(s.groups, []int64{11, 21, 21})
+
+	s.assertSelectionError(err, 2)
+}
+
+func (s *ModifiersTestSuite) TestOptionOfAnotherProductIsRejected() {
+	_, err := checkModifierSelection(s.groups, []int64{11, 99})
+
+	s.assertSelectionError(err, 0)
+}
+
+func (s *ModifiersTestSuite) TestProductWithoutGroupsTakesNoOptions() {
+	options, err := checkModifierSelection(nil, nil)
+	s.NoError(err)
+	s.Empty(options)
+
+	_, err = checkModifierSelection(nil, []int64{11})
+	s.ErrorIs(err, ErrInvalidModifiers)
+}
+
+func (s *ModifiersTestSuite) TestSameModifiersIgnoresOrder() {
+	item := models.CartItem{Modifiers: []models.CartItemModifiers{{ModifierOptionID: 21}, {ModifierOptionID: 12}}}
+
+	s.True(SameModifiers(item, []models.ModifierOptions{{ID: 12}, {ID: 21}}))
+	s.False(SameModifiers(item, []models.ModifierOptions{{ID: 12}}))
+	s.False(SameModifiers(item, []models.ModifierOptions{{ID: 12}, {ID: 22}}))
+}
+
+func (s *ModifiersTestSuite) assertSelectionError(err error, groupID int64) {
+	var selectionErr *ModifierSelectionError
+	s.True(errors.As(err, &selectionErr))
+	s.ErrorIs(err, ErrInvalidModifiers)
+	s.Equal(groupID, selectionErr.GroupID)
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"github.com/goravel/framework/contracts/mail"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+// NotifyAdmins emails every active admin account.
+func NotifyAdmins(subject, html string) error {
+	var emails []string
+	if err := facades.Orm().Query().Model(&models.Auth{}).Where("role = ? AND is_active = ?", "admin", true).Pluck("email", &emails); err != nil {
+		return err
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+
+	return facades.Mail().To(emails).Subject(subject).Content(mail.Content{Html: html}).Send()
+}
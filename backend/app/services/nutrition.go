@@ -0,0 +1,97 @@
+package services
+
+import (
+	"math"
+
+	"github.com/goravel/framework/contracts/database/orm"
+
+	"goravel/app/models"
+)
+
+// RecalculateProductNutrition sums the recipe of each product into its
+// product_nutrition row. Products without a recipe lose their row.
+func RecalculateProductNutrition(tx orm.Query, productIDs []int64) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+	ids := make([]any, 0, len(productIDs))
+	for _, id := range productIDs {
+		ids = append(ids, id)
+	}
+
+	var rows []struct {
+		ProductID int64
+		Kcal      float64
+		Protein   float64
+		Fat       float64
+		Carbs     float64
+		Sodium    float64
+		Missing   int
+	}
+	if err := tx.Raw(`SELECT product_ingredients.product_id,
+			SUM(product_ingredients.amount_used * ingredients.kcal) AS kcal,
+			SUM(product_ingredients.amount_used * ingredients.protein) AS protein,
+			SUM(product_ingredients.amount_used * ingredients.fat) AS fat,
+			SUM(product_ingredients.amount_used * ingredients.carbs) AS carbs,
+			SUM(product_ingredients.amount_used * ingredients.sodium) AS sodium,
+			COUNT(*) FILTER (WHERE NOT ingredients.has_nutrition) AS missing
+		FROM product_ingredients
+		JOIN ingredients ON ingredients.id = product_ingredients.ingredient_id
+		WHERE product_ingredients.product_id IN ?
+		GROUP BY product_ingredients.product_id`, productIDs).Scan(&rows); err != nil {
+		return err
+	}
+
+	if _, err := tx.Model(&models.ProductNutrition{}).WhereIn("product_id", ids).Delete(); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		nutrition := models.ProductNutrition{
+			ProductID:          row.ProductID,
+			Kcal:               roundNutrition(row.Kcal),
+			Protein:            roundNutrition(row.Protein),
+			Fat:                roundNutrition(row.Fat),
+			Carbs:              roundNutrition(row.Carbs),
+			Sodium:             roundNutrition(row.Sodium),
+			Complete:           row.Missing == 0,
+			MissingIngredients: row.Missing,
+		}
+		if err := tx.Create(&nutrition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RecalculateIngredientNutrition refreshes every product whose recipe uses
+// the ingredient.
+func RecalculateIngredientNutrition(tx orm.Query, ingredientID int64) error {
+	var recipe []models.ProductIngredient
+	if err := tx.Where("ingredient_id = ?", ingredientID).Find(&recipe); err != nil {
+		return err
+	}
+
+	productIDs := make([]int64, 0, len(recipe))
+	for _, item := range recipe {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	return RecalculateProductNutrition(tx, productIDs)
+}
+
+// ScaleNutrition returns the nutrition of a portion scaled by a variant's
+// recipe factor.
+func ScaleNutrition(nutrition models.ProductNutrition, factor float64) models.ProductNutrition {
+	nutrition.Kcal = roundNutrition(nutrition.Kcal * factor)
+	nutrition.Protein = roundNutrition(nutrition.Protein * factor)
+	nutrition.Fat = roundNutrition(nutrition.Fat * factor)
+	nutrition.Carbs = roundNutrition(nutrition.Carbs * factor)
+	nutrition.Sodium = roundNutrition(nutrition.Sodium * factor)
+
+	return nutrition
+}
+
+func roundNutrition(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"time"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+const (
+	PriceSourceInitial   = "initial"
+	PriceSourceManual    = "manual"
+	PriceSourceScheduled = "scheduled"
+
+	PriceChangePending   = "pending"
+	PriceChangeApplied   = "applied"
+	PriceChangeCancelled = "cancelled"
+)
+
+// RecordPriceChange closes the product's current price history row at the
+// given time and opens a new one with the new price. Nothing is written when
+// the price did not change.
+func RecordPriceChange(tx orm.Query, productID int64, price float64, source string, changedBy *int64, at time.Time) error {
+	var current models.ProductPriceHistory
+	if err := tx.Where("product_id = ? AND ended_at IS NULL", productID).OrderBy("started_at", "desc").First(&current); err != nil {
+		return err
+	}
+	if current.ID != 0 && current.Price == price {
+		return nil
+	}
+	if current.ID != 0 {
+		if _, err := tx.Model(&models.ProductPriceHistory{}).Where("product_id = ? AND ended_at IS NULL", productID).Update("ended_at", at); err != nil {
+			return err
+		}
+	}
+
+	return tx.Create(&models.ProductPriceHistory{
+		ProductID: productID,
+		Price:     price,
+		StartedAt: at,
+		Source:    source,
+		ChangedBy: changedBy,
+	})
+}
+
+// ApplyDuePriceChanges applies every pending price change whose effective
+// time has passed, oldest first, and returns the changes it applied.
+func ApplyDuePriceChanges(now time.Time) ([]models.ProductPriceChanges, error) {
+	var due []models.ProductPriceChanges
+	if err := facades.Orm().Query().Where("status = ? AND effective_at <= ?", PriceChangePending, now).
+		OrderBy("effective_at").OrderBy("id").Find(&due); err != nil {
+		return nil, err
+	}
+
+	applied := []models.ProductPriceChanges{}
+	for _, change := range due {
+		ok, err := applyPriceChange(change.ID, now)
+		if err != nil {
+			return applied, err
+		}
+		if ok {
+			change.Status = PriceChangeApplied
+			change.AppliedAt = &now
+			applied = append(applied, change)
+		}
+	}
+
+	return applied, nil
+}
+
+// applyPriceChange applies one pending change in its own transaction. It
+// returns false when the change was cancelled or applied in the meantime.
+func applyPriceChange(changeID int64, now time.Time) (bool, error) {
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return false, err
+	}
+
+	var change models.ProductPriceChanges
+	if err := tx.Model(&models.ProductPriceChanges{}).LockForUpdate().Where("id = ?", changeID).First(&change); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if change.ID == 0 || change.Status != PriceChangePending {
+		tx.Rollback()
+		return false, nil
+	}
+
+	if _, err := tx.WithTrashed().Model(&models.Product{}).Where("id = ?", change.ProductID).Update("price", change.Price); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if err := RecordPriceChange(tx, change.ProductID, change.Price, PriceSourceScheduled, &change.CreatedBy, change.EffectiveAt); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if _, err := tx.Model(&models.ProductPriceChanges{}).Where("id = ?", change.ID).Update(map[string]interface{}{
+		"status":     PriceChangeApplied,
+		"applied_at": now,
+	}); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// ProductPriceHistories loads the price history of the given products,
+// oldest first.
+func ProductPriceHistories(productIDs []any) (map[int64][]models.ProductPriceHistory, error) {
+	histories := map[int64][]models.ProductPriceHistory{}
+	if len(productIDs) == 0 {
+		return histories, nil
+	}
+
+	var rows []models.ProductPriceHistory
+	if err := facades.Orm().Query().WhereIn("product_id", productIDs).OrderBy("started_at").OrderBy("id").Find(&rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		histories[row.ProductID] = append(histories[row.ProductID], row)
+	}
+
+	return histories, nil
+}
+
+// PriceAt returns the list price from a product's history at the given time.
+// Sales before the first recorded price use the earliest known price.
+func PriceAt(history []models.ProductPriceHistory, at time.Time) (float64, bool) {
+	if len(history) == 0 {
+		return 0, false
+	}
+
+	price := history[0].Price
+	for _, row := range history {
+		if row.StartedAt.After(at) {
+			break
+		}
+		price = row.Price
+	}
+
+	return price, true
+}
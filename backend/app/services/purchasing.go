@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goravel/framework/contracts/database/orm"
+
+	"goravel/app/models"
+)
+
+// Purchase order statuses. A purchase order moves draft -> sent -> received
+// and can be cancelled until it has been received.
+const (
+	PurchaseOrderDraft     = "draft"
+	PurchaseOrderSent      = "sent"
+	PurchaseOrderReceived  = "received"
+	PurchaseOrderCancelled = "cancelled"
+)
+
+// ReceivePurchaseLine books a received purchase order line into stock as a new
+// batch and folds its unit cost into the ingredient's weighted average cost.
+func ReceivePurchaseLine(tx orm.Query, purchaseOrderID int64, line models.PurchaseOrderLines, expiresAt *time.Time, userID int64) error {
+	if line.ReceivedQuantity <= 0 {
+		return nil
+	}
+
+	var ingredient models.Ingredients
+	if err := tx.Model(&models.Ingredients{}).LockForUpdate().Where("id = ?", line.IngredientID).First(&ingredient); err != nil {
+		return err
+	}
+	if ingredient.ID == 0 {
+		return ErrIngredientNotFound
+	}
+
+	unitCost := line.UnitCost
+	if ingredient.Quantity > 0 {
+		unitCost = (ingredient.Quantity*ingredient.UnitCost + line.ReceivedQuantity*line.UnitCost) / (ingredient.Quantity + line.ReceivedQuantity)
+	}
+	if _, err := tx.Model(&models.Ingredients{}).Where("id = ?", ingredient.ID).Update("unit_cost", unitCost); err != nil {
+		return err
+	}
+
+	_, err := ReceiveStock(tx, models.IngredientBatches{
+		IngredientID:    line.IngredientID,
+		Quantity:        line.ReceivedQuantity,
+		UnitCost:        line.UnitCost,
+		ExpiresAt:       expiresAt,
+		PurchaseOrderID: &purchaseOrderID,
+	}, StockMovementPurchase, fmt.Sprintf("Purchase order #%d", purchaseOrderID), userID)
+
+	return err
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+// recommendationCacheTTL bounds how long a product's recommendations are
+// served from cache after the nightly job replaced them.
+const recommendationCacheTTL = 10 * time.Minute
+
+// RecommendedProduct is a product suggested next to the given one or to the
+// cart, with the co-purchase figures behind the suggestion.
+type RecommendedProduct struct {
+	models.Product
+	Orders     int     `json:"orders"`
+	Confidence float64 `json:"confidence"`
+	Lift       float64 `json:"lift"`
+}
+
+// ComputeRecommendations rebuilds product_recommendations from the completed
+// orders of the last days. Only products ordered separately count, combo
+// components always come together. Pairs seen in fewer than minOrders orders
+// are ignored and each product keeps its limit best matches. It returns the
+// number of products that got recommendations.
+func ComputeRecommendations(days, minOrders, limit int, now time.Time) (int, error) {
+	var pairs []struct {
+		ProductID            int64
+		RecommendedProductID int64
+		Orders               int
+		Confidence           float64
+		Lift                 float64
+	}
+	if err := facades.Orm().Query().Raw(`WITH baskets AS (
+			SELECT DISTINCT order_items.order_id, order_items.product_id
+			FROM order_items
+			JOIN orders ON orders.id = order_items.order_id
+			WHERE orders.status = 'completed' AND orders.created_at >= ? AND order_items.order_combo_id IS NULL
+		),
+		product_orders AS (SELECT product_id, COUNT(*) AS orders FROM baskets GROUP BY product_id),
+		total AS (SELECT COUNT(DISTINCT order_id) AS orders FROM baskets)
+		SELECT a.product_id, b.product_id AS recommended_product_id, COUNT(*) AS orders,
+			COUNT(*)::float / pa.orders AS confidence,
+			(COUNT(*)::float / pa.orders) / (pb.orders::float / total.orders) AS lift
+		FROM baskets a
+		JOIN baskets b ON b.order_id = a.order_id AND b.product_id <> a.product_id
+		JOIN product_orders pa ON pa.product_id = a.product_id
+		JOIN product_orders pb ON pb.product_id = b.product_id
+		CROSS JOIN total
+		GROUP BY a.product_id, b.product_id, pa.orders, pb.orders, total.orders
+		HAVING COUNT(*) >= ?`, now.AddDate(0, 0, -days), minOrders).Scan(&pairs); err != nil {
+		return 0, err
+	}
+
+	byProduct := map[int64][]models.ProductRecommendations{}
+	for _, pair := range pairs {
+		byProduct[pair.ProductID] = append(byProduct[pair.ProductID], models.ProductRecommendations{
+			ProductID:            pair.ProductID,
+			RecommendedProductID: pair.RecommendedProductID,
+			Orders:               pair.Orders,
+			Confidence:           pair.Confidence,
+			Lift:                 pair.Lift,
+			ComputedAt:           now,
+		})
+	}
+
+	tx, err := facades.Orm().Query().Begin()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("DELETE FROM product_recommendations"); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	for _, rows := range byProduct {
+		sort.Slice(rows, func(i, j int) bool {
+			if rows[i].Confidence != rows[j].Confidence {
+				return rows[i].Confidence > rows[j].Confidence
+			}
+			if rows[i].Lift != rows[j].Lift {
+				return rows[i].Lift > rows[j].Lift
+			}
+			return rows[i].RecommendedProductID < rows[j].RecommendedProductID
+		})
+		if len(rows) > limit {
+			rows = rows[:limit]
+		}
+		for rank := range rows {
+			rows[rank].Rank = rank + 1
+			if err := tx.Create(&rows[rank]); err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(byProduct), nil
+}
+
+// cachedRecommendations returns the stored recommendations of a product,
+// best first, through the cache.
+func cachedRecommendations(productID int64) ([]models.ProductRecommendations, error) {
+	key := fmt.Sprintf("recommendations:product:%d", productID)
+	rows := []models.ProductRecommendations{}
+	if cached := facades.Cache().GetString(key); cached != "" {
+		if err := json.Unmarshal([]byte(cached), &rows); err == nil {
+			return rows, nil
+		}
+	}
+
+	if err := facades.Orm().Query().Where("product_id = ?", productID).OrderBy("rank").Find(&rows); err != nil {
+		return nil, err
+	}
+	if encoded, err := json.Marshal(rows); err == nil {
+		if err := facades.Cache().Put(key, string(encoded), recommendationCacheTTL); err != nil {
+			facades.Log().Errorf("recommendation cache error: %v", err)
+		}
+	}
+
+	return rows, nil
+}
+
+// RecommendProducts combines the recommendations of the given products,
+// leaving out the products themselves and anything in exclude. Suggestions
+// shared by several products add up. Only products on sale right now are
+// returned, at most limit of them, with names in the given locale.
+func RecommendProducts(productIDs []int64, exclude map[int64]bool, limit int, locale string) ([]RecommendedProduct, error) {
+	type candidate struct {
+		orders     int
+		confidence float64
+		lift       float64
+	}
+	candidates := map[int64]*candidate{}
+	given := map[int64]bool{}
+	for _, productID := range productIDs {
+		given[productID] = true
+	}
+	for _, productID := range productIDs {
+		rows, err := cachedRecommendations(productID)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if given[row.RecommendedProductID] || exclude[row.RecommendedProductID] {
+				continue
+			}
+			item := candidates[row.RecommendedProductID]
+			if item == nil {
+				item = &candidate{}
+				candidates[row.RecommendedProductID] = item
+			}
+			item.orders += row.Orders
+			item.confidence += row.Confidence
+			if row.Lift > item.lift {
+				item.lift = row.Lift
+			}
+		}
+	}
+
+	result := []RecommendedProduct{}
+	if len(candidates) == 0 {
+		return result, nil
+	}
+
+	ids := make([]any, 0, len(candidates))
+	for id := range candidates {
+		ids = append(ids, id)
+	}
+	closedSchedules, err := ClosedScheduleIDs(RestaurantNow())
+	if err != nil {
+		return nil, err
+	}
+	var products []models.Product
+	if err := facades.Orm().Query().Model(&models.Product{}).WhereIn("id", ids).Where("status = ?", true).
+		Scopes(Sellable, OnSchedule(closedSchedules), InActiveCategory).Find(&products); err != nil {
+		return nil, err
+	}
+	if err := TranslateProducts(products, locale); err != nil {
+		return nil, err
+	}
+
+	for _, product := range products {
+		item := candidates[product.ID]
+		product.Available = true
+		result = append(result, RecommendedProduct{
+			Product:    product,
+			Orders:     item.orders,
+			Confidence: item.confidence,
+			Lift:       item.lift,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Confidence != result[j].Confidence {
+			return result[i].Confidence > result[j].Confidence
+		}
+		return result[i].Orders > result[j].Orders
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+var ErrInvalidClock = errors.New("time must be in HH:MM format")
+
+// RestaurantLocation returns the configured restaurant timezone.
+func RestaurantLocation() *time.Location {
+	location, err := time.LoadLocation(facades.Config().GetString("restaurant.timezone", "UTC"))
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+// RestaurantNow is the current time in the restaurant timezone.
+func RestaurantNow() time.Time {
+	return time.Now().In(RestaurantLocation())
+}
+
+// ParseClock turns "HH:MM" into minutes since midnight. "24:00" is accepted so
+// a period can run to the end of the day.
+func ParseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		if value == "24:00" {
+			return 24 * 60, nil
+		}
+		return 0, ErrInvalidClock
+	}
+
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// PeriodOpenAt reports whether a period covers the given restaurant time.
+// A period that ends before it starts runs into the next day and belongs to
+// the weekday it starts on.
+func PeriodOpenAt(period models.AvailabilityPeriods, at time.Time) bool {
+	start, err := ParseClock(period.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := ParseClock(period.EndTime)
+	if err != nil {
+		return false
+	}
+
+	minute := at.Hour()*60 + at.Minute()
+	weekday := int(at.Weekday())
+	if start < end {
+		return period.Weekday == weekday && minute >= start && minute < end
+	}
+
+	previous := (weekday + 6) % 7
+	return (period.Weekday == weekday && minute >= start) || (period.Weekday == previous && minute < end)
+}
+
+// ScheduleOpenAt reports whether any period of the schedule covers the time.
+func ScheduleOpenAt(schedule models.AvailabilitySchedules, at time.Time) bool {
+	for _, period := range schedule.Periods {
+		if PeriodOpenAt(period, at.In(RestaurantLocation())) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClosedScheduleIDs returns the schedules that are outside all their periods
+// at the given time.
+func ClosedScheduleIDs(at time.Time) ([]int64, error) {
+	var schedules []models.AvailabilitySchedules
+	if err := facades.Orm().Query().With("Periods").Find(&schedules); err != nil {
+		return nil, err
+	}
+
+	closed := []int64{}
+	for _, schedule := range schedules {
+		if !ScheduleOpenAt(schedule, at) {
+			closed = append(closed, schedule.ID)
+		}
+	}
+
+	return closed, nil
+}
+
+// productScheduleColumn is the schedule that applies to a product: its own,
+// or its category's when it has none.
+const productScheduleColumn = "COALESCE(products.availability_schedule_id, (SELECT categories.availability_schedule_id FROM categories WHERE categories.id = products.category_id), 0)"
+
+// OnSchedule returns a query scope that keeps only products whose schedule is
+// not in closedIDs.
+func OnSchedule(closedIDs []int64) func(orm.Query) orm.Query {
+	return func(query orm.Query) orm.Query {
+		if len(closedIDs) == 0 {
+			return query
+		}
+
+		return query.Where(productScheduleColumn+" NOT IN ?", closedIDs)
+	}
+}
+
+// OffScheduleProductIDs returns which of the given products cannot be sold at
+// the given time because of their schedule.
+func OffScheduleProductIDs(productIDs []int64, at time.Time) (map[int64]bool, error) {
+	offSchedule := map[int64]bool{}
+	if len(productIDs) == 0 {
+		return offSchedule, nil
+	}
+
+	closedIDs, err := ClosedScheduleIDs(at)
+	if err != nil || len(closedIDs) == 0 {
+		return offSchedule, err
+	}
+
+	var rows []struct {
+		ID int64
+	}
+	if err := facades.Orm().Query().Raw("SELECT products.id FROM products WHERE products.id IN ? AND "+productScheduleColumn+" IN ?", productIDs, closedIDs).Scan(&rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		offSchedule[row.ID] = true
+	}
+
+	return offSchedule, nil
+}
+
+// ProductOnSchedule reports whether a product can be sold at the given time.
+func ProductOnSchedule(productID int64, at time.Time) (bool, error) {
+	offSchedule, err := OffScheduleProductIDs([]int64{productID}, at)
+	if err != nil {
+		return false, err
+	}
+
+	return !offSchedule[productID], nil
+}
+
+// ValidatePeriod checks a period before it is saved.
+func ValidatePeriod(period models.AvailabilityPeriods) error {
+	if period.Weekday < 0 || period.Weekday > 6 {
+		return errors.New("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	start, err := ParseClock(period.StartTime)
+	if err != nil || start >= 24*60 {
+		return fmt.Errorf("start_time: %w", ErrInvalidClock)
+	}
+	end, err := ParseClock(period.EndTime)
+	if err != nil {
+		return fmt.Errorf("end_time: %w", ErrInvalidClock)
+	}
+	if start == end {
+		return errors.New("start_time and end_time cannot be equal")
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"goravel/app/models"
+)
+
+type SchedulesTestSuite struct {
+	suite.Suite
+}
+
+func TestSchedulesTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulesTestSuite))
+}
+
+// at returns the given time in the week of Sunday 2026-10-11.
+func at(weekday time.Weekday, clock string) time.Time {
+	parsed, _ := time.Parse("15:04", clock)
+
+	return time.Date(2026, 10, 11+int(weekday), parsed.Hour(), parsed.Minute(), 0, 0, time.UTC)
+}
+
+func (s *SchedulesTestSuite) TestParseClock() {
+	minute, err := ParseClock("07:30")
+	s.NoError(err)
+	s.Equal(450, minute)
+
+	minute, err = ParseClock("24:00")
+	s.NoError(err)
+	s.Equal(1440, minute)
+
+	_, err = ParseClock("7h30")
+	s.ErrorIs(err, ErrInvalidClock)
+}
+
+func (s *SchedulesTestSuite) TestPeriodWithinADay() {
+	period := models.AvailabilityPeriods{Weekday: int(time.Monday), StartTime: "09:00", EndTime: "17:00"}
+
+	s.Equal(time.Monday, at(time.Monday, "09:00").Weekday())
+	s.True(PeriodOpenAt(period, at(time.Monday, "09:00")))
+	s.True(PeriodOpenAt(period, at(time.Monday, "16:59")))
+	s.False(PeriodOpenAt(period, at(time.Monday, "17:00")))
+	s.False(PeriodOpenAt(period, at(time.Monday, "08:59")))
+	s.False(PeriodOpenAt(period, at(time.Tuesday, "10:00")))
+}
+
+func (s *SchedulesTestSuite) TestPeriodUntilMidnight() {
+	period := models.AvailabilityPeriods{Weekday: int(time.Friday), StartTime: "18:00", EndTime: "24:00"}
+
+	s.True(PeriodOpenAt(period, at(time.Friday, "23:59")))
+	s.False(PeriodOpenAt(period, at(time.Saturday, "00:00")))
+}
+
+func (s *SchedulesTestSuite) TestPeriodAcrossMidnight() {
+	period := models.AvailabilityPeriods{Weekday: int(time.Friday), StartTime: "22:00", EndTime: "02:00"}
+
+	s.False(PeriodOpenAt(period, at(time.Friday, "21:59")))
+	s.True(PeriodOpenAt(period, at(time.Friday, "22:00")))
+	s.True(PeriodOpenAt(period, at(time.Saturday, "01:59")))
+	s.False(PeriodOpenAt(period, at(time.Saturday, "02:00")))
+	s.False(PeriodOpenAt(period, at(time.Saturday, "23:00")))
+	s.False(PeriodOpenAt(period, at(time.Friday, "01:00")))
+}
+
+func (s *SchedulesTestSuite) TestPeriodAcrossMidnightIntoSunday() {
+	period := models.AvailabilityPeriods{Weekday: int(time.Saturday), StartTime: "20:00", EndTime: "01:00"}
+
+	s.Equal(time.Sunday, at(time.Sunday, "00:30").Weekday())
+	s.True(PeriodOpenAt(period, at(time.Sunday, "00:30")))
+	s.False(PeriodOpenAt(period, at(time.Sunday, "20:30")))
+}
+
+func (s *SchedulesTestSuite) TestPeriodWithBadClockIsClosed() {
+	period := models.AvailabilityPeriods{Weekday: int(time.Monday), StartTime: "9am", EndTime: "17:00"}
+
+	s.False(PeriodOpenAt(period, at(time.Monday, "10:00")))
+}
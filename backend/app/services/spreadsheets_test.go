@@ -0,0 +1,121 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SpreadsheetsTestSuite struct {
+	suite.Suite
+}
+
+func TestSpreadsheetsTestSuite(t *testing.T) {
+	suite.Run(t, new(SpreadsheetsTestSuite))
+}
+
+func (s *SpreadsheetsTestSuite) TestReadCSVStripsByteOrderMark() {
+	rows, err := ReadSpreadsheet("menu.CSV", []byte("\xef\xbb\xbfname, price\n\"Phở bò\",45000,extra\n"))
+
+	s.NoError(err)
+	s.Equal([][]string{{"name", "price"}, {"Phở bò", "45000", "extra"}}, rows)
+}
+
+func (s *SpreadsheetsTestSuite) TestReadCSVRejectsBrokenQuotes() {
+	_, err := ReadSpreadsheet("menu.csv", []byte("name\n\"Phở\"bò\n"))
+
+	s.ErrorIs(err, ErrSpreadsheetUnreadable)
+}
+
+func (s *SpreadsheetsTestSuite) TestUnsupportedExtension() {
+	_, err := ReadSpreadsheet("menu.xls", []byte{})
+
+	s.ErrorIs(err, ErrUnsupportedSpreadsheet)
+}
+
+func (s *SpreadsheetsTestSuite) TestWriteCSVAddsByteOrderMark() {
+	data, err := WriteCSV([][]any{{"name", "price"}, {"Cà phê", 25000.5}})
+	s.NoError(err)
+	s.True(bytes.HasPrefix(data, []byte("\xef\xbb\xbf")))
+
+	rows, err := ReadSpreadsheet("export.csv", data)
+	s.NoError(err)
+	s.Equal([][]string{{"name", "price"}, {"Cà phê", "25000.5"}}, rows)
+}
+
+func (s *SpreadsheetsTestSuite) TestXLSXRoundTrip() {
+	data, err := WriteXLSX("Menu", [][]any{
+		{"name", "price", "status"},
+		{"Bánh mì <đặc biệt>", 35000, true},
+		{"Trà đá", nil, false},
+	})
+	s.NoError(err)
+
+	rows, err := ReadSpreadsheet("menu.xlsx", data)
+
+	s.NoError(err)
+	s.Equal([][]string{
+		{"name", "price", "status"},
+		{"Bánh mì <đặc biệt>", "35000", "true"},
+		{"Trà đá", "", "false"},
+	}, rows)
+}
+
+func (s *SpreadsheetsTestSuite) TestReadXLSXWithSharedStrings() {
+	data := s.workbook(`<sst><si><t>name</t></si><si><r><t>Phở </t></r><r><t>gà</t></r></si></sst>`,
+		`<row r="1"><c r="A1" t="s"><v>0</v></c></row><row r="2"><c r="C2" t="s"><v>1</v></c><c><v>4</v></c></row>`)
+
+	rows, err := ReadSpreadsheet("menu.xlsx", data)
+
+	s.NoError(err)
+	s.Equal([][]string{{"name"}, {"", "", "Phở gà", "4"}}, rows)
+}
+
+func (s *SpreadsheetsTestSuite) TestReadXLSXRejectsBadCells() {
+	_, err := ReadSpreadsheet("menu.xlsx", s.workbook("", `<row r="1"><c r="XFE1"><v>1</v></c></row>`))
+	s.ErrorIs(err, ErrSpreadsheetUnreadable)
+
+	_, err = ReadSpreadsheet("menu.xlsx", s.workbook("", `<row r="1"><c r="A1" t="s"><v>3</v></c></row>`))
+	s.ErrorIs(err, ErrSpreadsheetUnreadable)
+
+	_, err = ReadSpreadsheet("menu.xlsx", []byte("not a zip"))
+	s.ErrorIs(err, ErrSpreadsheetUnreadable)
+}
+
+func (s *SpreadsheetsTestSuite) TestColumnIndex() {
+	s.Equal(0, columnIndex("A1"))
+	s.Equal(25, columnIndex("z9"))
+	s.Equal(26, columnIndex("AA3"))
+	s.Equal(xlsxMaxColumns-1, columnIndex("XFD1"))
+	s.Equal(-1, columnIndex("XFE1"))
+	s.Equal(-1, columnIndex("ZZZZZZZZZZZZZZ1"))
+	s.Equal("XFD", columnName(xlsxMaxColumns-1))
+	s.Equal("AA", columnName(26))
+}
+
+// workbook zips a one-sheet workbook around the given shared strings and
+// sheet rows.
+func (s *SpreadsheetsTestSuite) workbook(sharedStrings, sheetRows string) []byte {
+	files := map[string]string{
+		"xl/workbook.xml":            `<workbook><sheets><sheet name="Menu" sheetId="1" r:id="rId1" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml":   `<worksheet><sheetData>` + sheetRows + `</sheetData></worksheet>`,
+	}
+	if sharedStrings != "" {
+		files["xl/sharedStrings.xml"] = sharedStrings
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for name, content := range files {
+		writer, err := archive.Create(name)
+		s.Require().NoError(err)
+		_, err = writer.Write([]byte(content))
+		s.Require().NoError(err)
+	}
+	s.Require().NoError(archive.Close())
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/goravel/framework/contracts/database/orm"
+
+	"goravel/app/models"
+)
+
+// Stock movement types stored in stock_movements.type.
+const (
+	StockMovementPurchase   = "purchase"
+	StockMovementWaste      = "waste"
+	StockMovementCorrection = "correction"
+	StockMovementSale       = "sale"
+	StockMovementSaleCancel = "sale_cancel"
+	StockMovementStocktake  = "stocktake"
+)
+
+// stockEpsilon absorbs float rounding when batches are split.
+const stockEpsilon = 1e-9
+
+var (
+	ErrIngredientNotFound = errors.New("ingredient not found")
+	ErrInsufficientStock  = errors.New("insufficient stock")
+	ErrBatchNotFound      = errors.New("ingredient batch not found")
+)
+
+// AdjustStock applies change to the ingredient quantity inside tx and records
+// the movement. The ingredient row is locked so concurrent adjustments do not
+// overwrite each other. Added stock becomes a new batch without an expiry
+// date, removed stock is taken from the oldest batches first, which may
+// record one movement per batch touched.
+func AdjustStock(tx orm.Query, ingredientID int64, change float64, movementType, reason string, userID int64) ([]models.StockMovements, error) {
+	return applyMovement(tx, models.StockMovements{
+		IngredientID: ingredientID,
+		Type:         movementType,
+		Change:       change,
+		Reason:       reason,
+		UserID:       userID,
+	}, nil)
+}
+
+// ReceiveStock books batch.Quantity into stock as a new batch, keeping its
+// received and expiry dates.
+func ReceiveStock(tx orm.Query, batch models.IngredientBatches, movementType, reason string, userID int64) ([]models.StockMovements, error) {
+	return applyMovement(tx, models.StockMovements{
+		IngredientID: batch.IngredientID,
+		Type:         movementType,
+		Change:       batch.Quantity,
+		Reason:       reason,
+		UserID:       userID,
+	}, &batch)
+}
+
+// DeductOrderStock removes the recipe-weighted ingredient quantities of every
+// item in the order and marks the order as deducted. It must run inside the
+// same transaction as the status change.
+func DeductOrderStock(tx orm.Query, orderID int64, userID int64) error {
+	var orderItems []models.OrderItems
+	if err := tx.Where("order_id = ?", orderID).Find(&orderItems); err != nil {
+		return err
+	}
+	if len(orderItems) == 0 {
+		return markOrderStock(tx, orderID, true)
+	}
+
+	productIDs := make([]any, 0, len(orderItems))
+	for _, item := range orderItems {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	var recipes []models.ProductIngredient
+	if err := tx.WhereIn("product_id", productIDs).Find(&recipes); err != nil {
+		return err
+	}
+
+	required := orderStockRequired(orderItems, recipes)
+	reason := fmt.Sprintf("Order #%d", orderID)
+	for _, ingredientID := range sortedIngredientIDs(required) {
+		if _, err := applyMovement(tx, models.StockMovements{
+			IngredientID: ingredientID,
+			Type:         StockMovementSale,
+			Change:       -required[ingredientID],
+			OrderID:      &orderID,
+			Reason:       reason,
+			UserID:       userID,
+		}, nil); err != nil {
+			return err
+		}
+	}
+
+	return markOrderStock(tx, orderID, true)
+}
+
+// orderStockRequired sums the ingredient quantities the order lines use, each
+// line scaled by its quantity and recipe factor.
+func orderStockRequired(orderItems []models.OrderItems, recipes []models.ProductIngredient) map[int64]float64 {
+	recipeByProduct := map[int64][]models.ProductIngredient{}
+	for _, recipe := range recipes {
+		recipeByProduct[recipe.ProductID] = append(recipeByProduct[recipe.ProductID], recipe)
+	}
+
+	required := map[int64]float64{}
+	for _, item := range orderItems {
+		portions := float64(item.Quantity) * OrderItemFactor(item)
+		for _, recipe := range recipeByProduct[item.ProductID] {
+			required[recipe.IngredientID] += recipe.AmountUsed * portions
+		}
+	}
+
+	return required
+}
+
+// RestoreOrderStock puts back everything DeductOrderStock removed for the
+// order. It reverses the recorded movements rather than the current recipe so
+// recipe edits made after confirmation do not skew the stock, and returns each
+// quantity to the batch it was taken from.
+func RestoreOrderStock(tx orm.Query, orderID int64, userID int64) error {
+	var movements []models.StockMovements
+	if err := tx.Where("order_id = ? AND type IN ?", orderID, []string{StockMovementSale, StockMovementSaleCancel}).Find(&movements); err != nil {
+		return err
+	}
+
+	reason := fmt.Sprintf("Order #%d cancelled", orderID)
+	for _, movement := range reversalMovements(movements) {
+		movement.Type = StockMovementSaleCancel
+		movement.OrderID = &orderID
+		movement.Reason = reason
+		movement.UserID = userID
+		if _, err := applyMovement(tx, movement, nil); err != nil {
+			return err
+		}
+	}
+
+	return markOrderStock(tx, orderID, false)
+}
+
+// reversalMovements nets the recorded movements of an order per ingredient
+// and batch and returns what is still to be put back, ordered by ingredient
+// and batch.
+func reversalMovements(movements []models.StockMovements) []models.StockMovements {
+	type batchKey struct {
+		ingredientID int64
+		batchID      int64
+	}
+	deducted := map[batchKey]float64{}
+	for _, movement := range movements {
+		key := batchKey{ingredientID: movement.IngredientID}
+		if movement.BatchID != nil {
+			key.batchID = *movement.BatchID
+		}
+		deducted[key] -= movement.Change
+	}
+
+	keys := make([]batchKey, 0, len(deducted))
+	for key := range deducted {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ingredientID != keys[j].ingredientID {
+			return keys[i].ingredientID < keys[j].ingredientID
+		}
+		return keys[i].batchID < keys[j].batchID
+	})
+
+	reversals := []models.StockMovements{}
+	for _, key := range keys {
+		if deducted[key] <= stockEpsilon {
+			continue
+		}
+		movement := models.StockMovements{
+			IngredientID: key.ingredientID,
+			Change:       deducted[key],
+		}
+		if key.batchID != 0 {
+			batchID := key.batchID
+			movement.BatchID = &batchID
+		}
+		reversals = append(reversals, movement)
+	}
+
+	return reversals
+}
+
+// applyMovement is the only place ingredient quantities change. A positive
+// change with a BatchID goes back into that batch, any other positive change
+// opens a new batch (from newBatch when given). A negative change with a
+// BatchID is taken from that batch only, otherwise batches are consumed
+// oldest first.
+func applyMovement(tx orm.Query, movement models.StockMovements, newBatch *models.IngredientBatches) ([]models.StockMovements, error) {
+	var ingredient models.Ingredients
+	if err := tx.Model(&models.Ingredients{}).LockForUpdate().Where("id = ?", movement.IngredientID).First(&ingredient); err != nil {
+		return nil, err
+	}
+	if ingredient.ID == 0 {
+		return nil, ErrIngredientNotFound
+	}
+
+	quantity := ingredient.Quantity + movement.Change
+	if quantity < -stockEpsilon {
+		return nil, fmt.Errorf("%w: %s", ErrInsufficientStock, ingredient.Name)
+	}
+	if quantity < 0 {
+		quantity = 0
+	}
+
+	if _, err := tx.Model(&models.Ingredients{}).Where("id = ?", ingredient.ID).Update("quantity", quantity); err != nil {
+		return nil, err
+	}
+
+	if movement.Change > 0 {
+		if err := addToBatch(tx, &movement, ingredient, newBatch); err != nil {
+			return nil, err
+		}
+		movement.QuantityAfter = quantity
+		if err := tx.Create(&movement); err != nil {
+			return nil, err
+		}
+
+		return []models.StockMovements{movement}, nil
+	}
+
+	return takeFromBatches(tx, movement, ingredient.Quantity)
+}
+
+func addToBatch(tx orm.Query, movement *models.StockMovements, ingredient models.Ingredients, newBatch *models.IngredientBatches) error {
+	if movement.BatchID != nil {
+		var batch models.IngredientBatches
+		if err := tx.Model(&models.IngredientBatches{}).LockForUpdate().Where("id = ?", *movement.BatchID).First(&batch); err != nil {
+			return err
+		}
+		if batch.ID != 0 {
+			_, err := tx.Model(&models.IngredientBatches{}).Where("id = ?", batch.ID).Update("remaining", batch.Remaining+movement.Change)
+			return err
+		}
+	}
+
+	batch := models.IngredientBatches{ReceivedAt: time.Now(), UnitCost: ingredient.UnitCost}
+	if newBatch != nil {
+		batch = *newBatch
+		if batch.ReceivedAt.IsZero() {
+			batch.ReceivedAt = time.Now()
+		}
+	}
+	batch.IngredientID = ingredient.ID
+	batch.Quantity = movement.Change
+	batch.Remaining = movement.Change
+	if err := tx.Create(&batch); err != nil {
+		return err
+	}
+	movement.BatchID = &batch.ID
+
+	return nil
+}
+
+// takeFromBatches records one movement per batch the change was taken from.
+func takeFromBatches(tx orm.Query, movement models.StockMovements, quantityBefore float64) ([]models.StockMovements, error) {
+	query := tx.Model(&models.IngredientBatches{}).LockForUpdate().Where("ingredient_id = ? AND remaining > 0", movement.IngredientID)
+	if movement.BatchID != nil {
+		query = query.Where("id = ?", *movement.BatchID)
+	}
+
+	var batches []models.IngredientBatches
+	if err := query.OrderBy("received_at").OrderBy("id").Find(&batches); err != nil {
+		return nil, err
+	}
+
+	parts, err := splitAcrossBatches(movement, batches, quantityBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := map[int64]float64{}
+	for _, batch := range batches {
+		remaining[batch.ID] = batch.Remaining
+	}
+	for index := range parts {
+		if batchID := parts[index].BatchID; batchID != nil {
+			if _, err := tx.Model(&models.IngredientBatches{}).Where("id = ?", *batchID).Update("remaining", remaining[*batchID]+parts[index].Change); err != nil {
+				return nil, err
+			}
+		}
+		if err := tx.Create(&parts[index]); err != nil {
+			return nil, err
+		}
+	}
+
+	return parts, nil
+}
+
+// splitAcrossBatches divides a negative movement over the batches, oldest
+// received first, into one movement per batch touched. Anything the batches
+// cannot cover (stock recorded before batches existed) becomes a movement
+// without a batch, unless the movement names its batch.
+func splitAcrossBatches(movement models.StockMovements, batches []models.IngredientBatches, quantityBefore float64) ([]models.StockMovements, error) {
+	ordered := append([]models.IngredientBatches{}, batches...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if !ordered[i].ReceivedAt.Equal(ordered[j].ReceivedAt) {
+			return ordered[i].ReceivedAt.Before(ordered[j].ReceivedAt)
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	needed := -movement.Change
+	quantityAfter := quantityBefore
+	parts := []models.StockMovements{}
+	for _, batch := range ordered {
+		if needed <= stockEpsilon {
+			break
+		}
+		if batch.Remaining <= 0 {
+			continue
+		}
+		taken := math.Min(needed, batch.Remaining)
+
+		batchID := batch.ID
+		needed -= taken
+		quantityAfter -= taken
+		part := movement
+		part.ID = 0
+		part.BatchID = &batchID
+		part.Change = -taken
+		part.QuantityAfter = math.Max(quantityAfter, 0)
+		parts = append(parts, part)
+	}
+
+	if needed > stockEpsilon {
+		if movement.BatchID != nil {
+			return nil, fmt.Errorf("%w: batch #%d", ErrInsufficientStock, *movement.BatchID)
+		}
+		part := movement
+		part.Change = -needed
+		part.QuantityAfter = math.Max(quantityAfter-needed, 0)
+		parts = append(parts, part)
+	}
+
+	return parts, nil
+}
+
+func markOrderStock(tx orm.Query, orderID int64, deducted bool) error {
+	_, err := tx.Model(&models.Orders{}).Where("id = ?", orderID).Update("stock_deducted", deducted)
+	return err
+}
+
+// sortedIngredientIDs returns the keys in ascending order so rows are always
+// locked in the same order and concurrent orders cannot deadlock.
+func sortedIngredientIDs(quantities map[int64]float64) []int64 {
+	ids := make([]int64, 0, len(quantities))
+	for id := range quantities {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
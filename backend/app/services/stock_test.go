@@ -0,0 +1,182 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"goravel/app/models"
+)
+
+type StockTestSuite struct {
+	suite.Suite
+	batches []models.IngredientBatches
+}
+
+func TestStockTestSuite(t *testing.T) {
+	suite.Run(t, new(StockTestSuite))
+}
+
+// SetupTest will run before each test in the suite.
+func (s *StockTestSuite) SetupTest() {
+	received := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	// Listed newest first to check the split does not rely on the query order.
+	s.batches = []models.IngredientBatches{
+		{ID: 3, IngredientID: 10, Quantity: 4, Remaining: 4, ReceivedAt: received.AddDate(0, 0, 2)},
+		{ID: 2, IngredientID: 10, Quantity: 5, Remaining: 3, ReceivedAt: received},
+		{ID: 1, IngredientID: 10, Quantity: 2, Remaining: 2, ReceivedAt: received},
+	}
+}
+
+func (s *StockTestSuite) TestSplitAcrossBatchesTakesOldestFirst() {
+	parts, err := splitAcrossBatches(models.StockMovements{IngredientID: 10, Change: -6}, s.batches, 9)
+
+	s.NoError(err)
+	s.Len(parts, 3)
+	s.Equal(int64(1), *parts[0].BatchID)
+	s.InDelta(-2, parts[0].Change, stockEpsilon)
+	s.InDelta(7, parts[0].QuantityAfter, stockEpsilon)
+	s.Equal(int64(2), *parts[1].BatchID)
+	s.InDelta(-3, parts[1].Change, stockEpsilon)
+	s.Equal(int64(3), *parts[2].BatchID)
+	s.InDelta(-1, parts[2].Change, stockEpsilon)
+	s.InDelta(3, parts[2].QuantityAfter, stockEpsilon)
+}
+
+func (s *StockTestSuite) TestSplitAcrossBatchesSkipsEmptyBatches() {
+	s.batches[2].Remaining = 0
+
+	parts, err := splitAcrossBatches(models.StockMovements{IngredientID: 10, Change: -1}, s.batches, 7)
+
+	s.NoError(err)
+	s.Len(parts, 1)
+	s.Equal(int64(2), *parts[0].BatchID)
+}
+
+func (s *StockTestSuite) TestSplitAcrossBatchesRecordsUncoveredStockWithoutBatch() {
+	parts, err := splitAcrossBatches(models.StockMovements{IngredientID: 10, Change: -12}, s.batches, 12)
+
+	s.NoError(err)
+	s.Len(parts, 4)
+	s.Nil(parts[3].BatchID)
+	s.InDelta(-3, parts[3].Change, stockEpsilon)
+	s.InDelta(0, parts[3].QuantityAfter, stockEpsilon)
+}
+
+func (s *StockTestSuite) TestSplitAcrossBatchesRejectsShortNamedBatch() {
+	batchID := int64(2)
+
+	_, err := splitAcrossBatches(models.StockMovements{IngredientID: 10, Change: -4, BatchID: &batchID}, s.batches[1:2], 9)
+
+	s.ErrorIs(err, ErrInsufficientStock)
+}
+
+func (s *StockTestSuite) TestOrderStockRequiredSumsRecipesByQuantity() {
+	items := []models.OrderItems{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 1, Quantity: 1},
+		{ProductID: 2, Quantity: 3},
+		{ProductID: 3, Quantity: 5},
+	}
+	recipes := []models.ProductIngredient{
+		{ProductID: 1, IngredientID: 10, AmountUsed: 100},
+		{ProductID: 1, IngredientID: 11, AmountUsed: 2},
+		{ProductID: 2, IngredientID: 10, AmountUsed: 50},
+	}
+
+	required := orderStockRequired(items, recipes)
+
+	s.Len(required, 2)
+	s.InDelta(450, required[10], stockEpsilon)
+	s.InDelta(6, required[11], stockEpsilon)
+}
+
+func (s *StockTestSuite) TestOrderStockRequiredScalesByQuantityAndFactor() {
+	items := []models.OrderItems{
+		{ProductID: 1, Quantity: 2, RecipeFactor: 1.5},
+		{ProductID: 1, Quantity: 1},
+		{ProductID: 2, Quantity: 3, RecipeFactor: 1},
+		{ProductID: 3, Quantity: 5, RecipeFactor: 1},
+	}
+	recipes := []models.ProductIngredient{
+		{ProductID: 1, IngredientID: 10, AmountUsed: 100},
+		{ProductID: 1, IngredientID: 11, AmountUsed: 2},
+		{ProductID: 2, IngredientID: 10, AmountUsed: 50},
+	}
+
+	required := orderStockRequired(items, recipes)
+
+	s.Len(required, 2)
+	s.InDelta(550, required[10], stockEpsilon)
+	s.InDelta(8, required[11], stockEpsilon)
+}
+
+func (s *StockTestSuite) TestDeductAndRestoreRoundTrip() {
+	before := s.remaining()
+	sold, err := splitAcrossBatches(models.StockMovements{IngredientID: 10, Change: -12, Type: StockMovementSale}, s.batches, 12)
+	s.NoError(err)
+	s.applyToBatches(sold)
+
+	reversals := reversalMovements(sold)
+	s.applyToBatches(reversals)
+
+	s.Equal(before, s.remaining())
+	s.Len(reversals, 4)
+	s.Nil(reversals[0].BatchID)
+	s.InDelta(3, reversals[0].Change, stockEpsilon)
+}
+
+func (s *StockTestSuite) TestRestoreSkipsWhatWasAlreadyPutBack() {
+	sold, err := splitAcrossBatches(models.StockMovements{IngredientID: 10, Change: -6, Type: StockMovementSale}, s.batches, 9)
+	s.NoError(err)
+	restored := reversalMovements(sold)
+	for index := range restored {
+		restored[index].Type = StockMovementSaleCancel
+	}
+
+	s.Empty(reversalMovements(append(sold, restored...)))
+}
+
+func (s *StockTestSuite) TestRestoreAfterUnitChange() {
+	before := s.remaining()
+	sold, err := splitAcrossBatches(models.StockMovements{IngredientID: 10, Change: -6, Type: StockMovementSale}, s.batches, 9)
+	s.NoError(err)
+	s.applyToBatches(sold)
+
+	// What ChangeIngredientUnit rewrites when going from kg to g.
+	ratio, err := UnitChangeRatio("kg", "g")
+	s.NoError(err)
+	for index := range s.batches {
+		s.batches[index].Quantity *= ratio
+		s.batches[index].Remaining *= ratio
+	}
+	for index := range sold {
+		sold[index].Change *= ratio
+	}
+
+	s.applyToBatches(reversalMovements(sold))
+
+	for batchID, remaining := range s.remaining() {
+		s.InDelta(before[batchID]*1000, remaining, stockEpsilon)
+	}
+}
+
+func (s *StockTestSuite) applyToBatches(movements []models.StockMovements) {
+	for _, movement := range movements {
+		for index := range s.batches {
+			if movement.BatchID != nil && s.batches[index].ID == *movement.BatchID {
+				s.batches[index].Remaining += movement.Change
+			}
+		}
+	}
+}
+
+func (s *StockTestSuite) remaining() map[int64]float64 {
+	remaining := map[int64]float64{}
+	for _, batch := range s.batches {
+		remaining[batch.ID] = batch.Remaining
+	}
+
+	return remaining
+}
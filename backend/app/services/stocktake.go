@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/goravel/framework/contracts/database/orm"
+
+	"goravel/app/models"
+)
+
+// Stocktake statuses. Counts can only be entered while a stocktake is open.
+const (
+	StocktakeOpen      = "open"
+	StocktakeClosed    = "closed"
+	StocktakeCancelled = "cancelled"
+)
+
+// StocktakeVarianceLine compares the expected and counted quantity of one
+// ingredient. A negative variance means stock went missing.
+type StocktakeVarianceLine struct {
+	IngredientID  int64   `json:"ingredient_id"`
+	Name          string  `json:"name"`
+	Unit          string  `json:"unit"`
+	Expected      float64 `json:"expected_quantity"`
+	Counted       float64 `json:"counted_quantity"`
+	Variance      float64 `json:"variance"`
+	UnitCost      float64 `json:"unit_cost"`
+	VarianceValue float64 `json:"variance_value"`
+}
+
+type StocktakeVariance struct {
+	StocktakeID    int64                   `json:"stocktake_id"`
+	Status         string                  `json:"status"`
+	Lines          []StocktakeVarianceLine `json:"lines"`
+	ShrinkageValue float64                 `json:"shrinkage_value"`
+	SurplusValue   float64                 `json:"surplus_value"`
+	NetValue       float64                 `json:"net_value"`
+}
+
+// RecordStocktakeCount stores the counted quantity of an ingredient together
+// with the system quantity at that moment. The count may be given in any unit
+// of the ingredient's dimension. Counting the same ingredient again replaces
+// the previous count.
+func RecordStocktakeCount(tx orm.Query, stocktakeID, ingredientID int64, counted float64, unit string, userID int64) (models.StocktakeLines, error) {
+	var ingredient models.Ingredients
+	if err := tx.Where("id = ?", ingredientID).First(&ingredient); err != nil {
+		return models.StocktakeLines{}, err
+	}
+	if ingredient.ID == 0 {
+		return models.StocktakeLines{}, ErrIngredientNotFound
+	}
+
+	counted, err := ConvertQuantity(counted, unit, ingredient.Unit)
+	if err != nil {
+		return models.StocktakeLines{}, err
+	}
+
+	var line models.StocktakeLines
+	if err := tx.Where("stocktake_id = ? AND ingredient_id = ?", stocktakeID, ingredientID).First(&line); err != nil {
+		return models.StocktakeLines{}, err
+	}
+
+	line.StocktakeID = stocktakeID
+	line.IngredientID = ingredientID
+	line.ExpectedQuantity = ingredient.Quantity
+	line.CountedQuantity = counted
+	line.CountedBy = userID
+	line.CountedAt = time.Now()
+	if err := tx.Save(&line); err != nil {
+		return models.StocktakeLines{}, err
+	}
+	line.Ingredient = ingredient
+
+	return line, nil
+}
+
+// CloseStocktake books the difference between counted and expected quantity
+// of every line as a stocktake movement and freezes the unit costs used to
+// value the variance. The difference is applied as a delta so sales made
+// after an ingredient was counted are kept.
+func CloseStocktake(tx orm.Query, stocktakeID int64, userID int64) error {
+	var lines []models.StocktakeLines
+	if err := tx.Where("stocktake_id = ?", stocktakeID).With("Ingredient").Find(&lines); err != nil {
+		return err
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].IngredientID < lines[j].IngredientID })
+
+	reason := fmt.Sprintf("Stocktake #%d", stocktakeID)
+	for _, line := range lines {
+		if _, err := tx.Model(&models.StocktakeLines{}).Where("id = ?", line.ID).Update("unit_cost", line.Ingredient.UnitCost); err != nil {
+			return err
+		}
+
+		variance := line.CountedQuantity - line.ExpectedQuantity
+		if variance == 0 {
+			continue
+		}
+		if _, err := AdjustStock(tx, line.IngredientID, variance, StockMovementStocktake, reason, userID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	_, err := tx.Model(&models.Stocktakes{}).Where("id = ?", stocktakeID).Update(map[string]interface{}{
+		"status":    StocktakeClosed,
+		"closed_by": userID,
+		"closed_at": now,
+	})
+
+	return err
+}
+
+// BuildStocktakeVariance values each line's variance at cost. Open stocktakes
+// use the ingredients' current cost, closed ones the cost frozen on close.
+func BuildStocktakeVariance(stocktake models.Stocktakes) StocktakeVariance {
+	report := StocktakeVariance{
+		StocktakeID: stocktake.ID,
+		Status:      stocktake.Status,
+		Lines:       []StocktakeVarianceLine{},
+	}
+
+	for _, line := range stocktake.Lines {
+		unitCost := line.UnitCost
+		if stocktake.Status == StocktakeOpen {
+			unitCost = line.Ingredient.UnitCost
+		}
+
+		variance := line.CountedQuantity - line.ExpectedQuantity
+		value := variance * unitCost
+		report.Lines = append(report.Lines, StocktakeVarianceLine{
+			IngredientID:  line.IngredientID,
+			Name:          line.Ingredient.Name,
+			Unit:          line.Ingredient.Unit,
+			Expected:      line.ExpectedQuantity,
+			Counted:       line.CountedQuantity,
+			Variance:      variance,
+			UnitCost:      unitCost,
+			VarianceValue: value,
+		})
+
+		if value < 0 {
+			report.ShrinkageValue -= value
+		} else {
+			report.SurplusValue += value
+		}
+		report.NetValue += value
+	}
+
+	// Biggest losses first
+	sort.Slice(report.Lines, func(i, j int) bool { return report.Lines[i].VarianceValue < report.Lines[j].VarianceValue })
+
+	return report
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+const (
+	TagTypeDietary  = "dietary"
+	TagTypeAllergen = "allergen"
+)
+
+// productTagsSQL lists every (product_id, tag_id) pair: tags set on the
+// product plus allergens carried by the ingredients in its recipe.
+const productTagsSQL = `SELECT product_id, tag_id FROM product_tags
+	UNION
+	SELECT pi.product_id, it.tag_id FROM product_ingredients pi
+	JOIN ingredient_tags it ON it.ingredient_id = pi.ingredient_id`
+
+// ParseTagSlugs splits a comma separated query value into lower case slugs.
+func ParseTagSlugs(value string) []string {
+	slugs := []string{}
+	for _, slug := range strings.Split(value, ",") {
+		if slug = strings.ToLower(strings.TrimSpace(slug)); slug != "" {
+			slugs = append(slugs, slug)
+		}
+	}
+
+	return slugs
+}
+
+// WithAllTags keeps products carrying every one of the given tags.
+func WithAllTags(slugs []string) func(orm.Query) orm.Query {
+	return func(query orm.Query) orm.Query {
+		return query.Where(`products.id IN (SELECT pt.product_id FROM (`+productTagsSQL+`) pt
+			JOIN tags t ON t.id = pt.tag_id WHERE t.slug IN ?
+			GROUP BY pt.product_id HAVING COUNT(DISTINCT t.id) = ?)`, slugs, len(slugs))
+	}
+}
+
+// WithoutTags drops products carrying any of the given tags, including
+// allergens that only come from their ingredients.
+func WithoutTags(slugs []string) func(orm.Query) orm.Query {
+	return func(query orm.Query) orm.Query {
+		return query.Where(`products.id NOT IN (SELECT pt.product_id FROM (`+productTagsSQL+`) pt
+			JOIN tags t ON t.id = pt.tag_id WHERE t.slug IN ?)`, slugs)
+	}
+}
+
+// ProductTags loads the tags of each product. Tags only inherited from an
+// ingredient are marked Derived.
+func ProductTags(productIDs []int64) (map[int64][]models.Tags, error) {
+	result := map[int64][]models.Tags{}
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+	ids := make([]any, 0, len(productIDs))
+	for _, id := range productIDs {
+		ids = append(ids, id)
+	}
+
+	var links []models.ProductTags
+	if err := facades.Orm().Query().WhereIn("product_id", ids).With("Tag").Find(&links); err != nil {
+		return nil, err
+	}
+
+	type derivedRow struct {
+		ProductID int64
+		TagID     int64
+	}
+	var derived []derivedRow
+	if err := facades.Orm().Query().Raw(`SELECT DISTINCT pi.product_id, it.tag_id FROM product_ingredients pi
+		JOIN ingredient_tags it ON it.ingredient_id = pi.ingredient_id
+		WHERE pi.product_id IN ?`, productIDs).Scan(&derived); err != nil {
+		return nil, err
+	}
+
+	tagIDs := []any{}
+	for _, row := range derived {
+		tagIDs = append(tagIDs, row.TagID)
+	}
+	tagsByID := map[int64]models.Tags{}
+	if len(tagIDs) > 0 {
+		var tags []models.Tags
+		if err := facades.Orm().Query().WhereIn("id", tagIDs).Find(&tags); err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			tagsByID[tag.ID] = tag
+		}
+	}
+
+	seen := map[int64]map[int64]bool{}
+	add := func(productID int64, tag models.Tags) {
+		if seen[productID] == nil {
+			seen[productID] = map[int64]bool{}
+		}
+		if seen[productID][tag.ID] {
+			return
+		}
+		seen[productID][tag.ID] = true
+		result[productID] = append(result[productID], tag)
+	}
+	for _, link := range links {
+		add(link.ProductID, link.Tag)
+	}
+	for _, row := range derived {
+		if tag, ok := tagsByID[row.TagID]; ok {
+			tag.Derived = true
+			add(row.ProductID, tag)
+		}
+	}
+
+	for productID := range result {
+		tags := result[productID]
+		sort.Slice(tags, func(i, j int) bool {
+			if tags[i].Type != tags[j].Type {
+				return tags[i].Type > tags[j].Type
+			}
+			return tags[i].Name < tags[j].Name
+		})
+	}
+
+	return result, nil
+}
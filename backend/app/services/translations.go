@@ -0,0 +1,180 @@
+package services
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+// FallbackLocale is the locale used when a translation is missing in the
+// requested one.
+func FallbackLocale() string {
+	return normalizeLocale(facades.Config().GetString("app.fallback_locale", "en"))
+}
+
+// SupportedLocales lists the locales content can be translated into.
+func SupportedLocales() []string {
+	locales := []string{}
+	seen := map[string]bool{}
+	for _, locale := range append(strings.Split(facades.Config().GetString("app.supported_locales", "vi,en"), ","), FallbackLocale()) {
+		if locale = normalizeLocale(locale); locale != "" && !seen[locale] {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+
+	return locales
+}
+
+// IsSupportedLocale reports whether content can be stored in the locale.
+func IsSupportedLocale(locale string) bool {
+	for _, supported := range SupportedLocales() {
+		if supported == locale {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeLocale reduces a language tag such as "en-US" to "en".
+func normalizeLocale(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if index := strings.IndexAny(tag, "-_"); index >= 0 {
+		tag = tag[:index]
+	}
+
+	return tag
+}
+
+// ResolveLocale picks the content locale from the lang query parameter, then
+// the Accept-Language header by preference, then the fallback locale.
+func ResolveLocale(lang, acceptLanguage string) string {
+	if locale := normalizeLocale(lang); IsSupportedLocale(locale) {
+		return locale
+	}
+
+	type preference struct {
+		locale  string
+		quality float64
+	}
+	preferences := []preference{}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		item := preference{locale: normalizeLocale(fields[0]), quality: 1}
+		for _, field := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(field), "q="); ok {
+				if quality, err := strconv.ParseFloat(value, 64); err == nil {
+					item.quality = quality
+				}
+			}
+		}
+		if item.locale != "" && item.quality > 0 {
+			preferences = append(preferences, item)
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].quality > preferences[j].quality })
+	for _, item := range preferences {
+		if IsSupportedLocale(item.locale) {
+			return item.locale
+		}
+	}
+
+	return FallbackLocale()
+}
+
+// translationLocales is the lookup order for a request locale.
+func translationLocales(locale string) []any {
+	if fallback := FallbackLocale(); fallback != locale {
+		return []any{locale, fallback}
+	}
+
+	return []any{locale}
+}
+
+// TranslateProducts replaces product names and descriptions, and the names
+// of preloaded categories, with their translation in the locale or the
+// fallback locale. Untranslated fields keep their stored value.
+func TranslateProducts(products []models.Product, locale string) error {
+	if len(products) == 0 {
+		return nil
+	}
+	productIDs := make([]any, 0, len(products))
+	categories := []models.Category{}
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+		if product.Category.ID != 0 {
+			categories = append(categories, product.Category)
+		}
+	}
+
+	var rows []models.ProductTranslations
+	if err := facades.Orm().Query().WhereIn("product_id", productIDs).WhereIn("locale", translationLocales(locale)).Find(&rows); err != nil {
+		return err
+	}
+	translations := map[int64]models.ProductTranslations{}
+	for _, row := range rows {
+		if current, ok := translations[row.ProductID]; !ok || current.Locale != locale {
+			translations[row.ProductID] = row
+		}
+	}
+
+	if err := TranslateCategories(categories, locale); err != nil {
+		return err
+	}
+	categoryNames := map[int64]string{}
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	for i := range products {
+		if translation, ok := translations[products[i].ID]; ok {
+			if translation.Name != "" {
+				products[i].Name = translation.Name
+			}
+			if translation.Description != "" {
+				products[i].Description = translation.Description
+			}
+		}
+		if name, ok := categoryNames[products[i].Category.ID]; ok {
+			products[i].Category.Name = name
+		}
+	}
+
+	return nil
+}
+
+// TranslateCategories replaces category names with their translation in the
+// locale or the fallback locale.
+func TranslateCategories(categories []models.Category, locale string) error {
+	if len(categories) == 0 {
+		return nil
+	}
+	categoryIDs := make([]any, 0, len(categories))
+	for _, category := range categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+
+	var rows []models.CategoryTranslations
+	if err := facades.Orm().Query().WhereIn("category_id", categoryIDs).WhereIn("locale", translationLocales(locale)).Find(&rows); err != nil {
+		return err
+	}
+	translations := map[int64]models.CategoryTranslations{}
+	for _, row := range rows {
+		if current, ok := translations[row.CategoryID]; !ok || current.Locale != locale {
+			translations[row.CategoryID] = row
+		}
+	}
+
+	for i := range categories {
+		if translation, ok := translations[categories[i].ID]; ok && translation.Name != "" {
+			categories[i].Name = translation.Name
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goravel/framework/contracts/database/orm"
+
+	"goravel/app/models"
+)
+
+// Unit dimensions. Quantities can only be converted within one dimension.
+const (
+	UnitDimensionMass   = "mass"
+	UnitDimensionVolume = "volume"
+	UnitDimensionCount  = "count"
+)
+
+var (
+	ErrUnknownUnit       = errors.New("unknown unit")
+	ErrIncompatibleUnits = errors.New("incompatible units")
+)
+
+// Unit is a registered unit of measure. Factor converts one of this unit into
+// the base unit of its dimension (g, ml or piece).
+type Unit struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Dimension string  `json:"dimension"`
+	Factor    float64 `json:"factor"`
+}
+
+var unitRegistry = map[string]Unit{
+	"mg":    {Code: "mg", Name: "Milligram", Dimension: UnitDimensionMass, Factor: 0.001},
+	"g":     {Code: "g", Name: "Gram", Dimension: UnitDimensionMass, Factor: 1},
+	"kg":    {Code: "kg", Name: "Kilogram", Dimension: UnitDimensionMass, Factor: 1000},
+	"ml":    {Code: "ml", Name: "Millilitre", Dimension: UnitDimensionVolume, Factor: 1},
+	"l":     {Code: "l", Name: "Litre", Dimension: UnitDimensionVolume, Factor: 1000},
+	"piece": {Code: "piece", Name: "Piece", Dimension: UnitDimensionCount, Factor: 1},
+	"dozen": {Code: "dozen", Name: "Dozen", Dimension: UnitDimensionCount, Factor: 12},
+}
+
+// unitAliases maps spellings found in existing data onto registry codes.
+var unitAliases = map[string]string{
+	"gr":         "g",
+	"gram":       "g",
+	"grams":      "g",
+	"kilogram":   "kg",
+	"kilograms":  "kg",
+	"kgs":        "kg",
+	"milligram":  "mg",
+	"millilitre": "ml",
+	"milliliter": "ml",
+	"litre":      "l",
+	"liter":      "l",
+	"lit":        "l",
+	"lít":        "l",
+	"pcs":        "piece",
+	"pc":         "piece",
+	"pieces":     "piece",
+	"cái":        "piece",
+	"quả":        "piece",
+}
+
+// Units returns the registry ordered by dimension and size.
+func Units() []Unit {
+	units := make([]Unit, 0, len(unitRegistry))
+	for _, unit := range unitRegistry {
+		units = append(units, unit)
+	}
+	sort.Slice(units, func(i, j int) bool {
+		if units[i].Dimension != units[j].Dimension {
+			return units[i].Dimension < units[j].Dimension
+		}
+		return units[i].Factor < units[j].Factor
+	})
+
+	return units
+}
+
+// LookupUnit finds a unit by code or alias, ignoring case and spaces.
+func LookupUnit(code string) (Unit, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if alias, ok := unitAliases[code]; ok {
+		code = alias
+	}
+	unit, ok := unitRegistry[code]
+
+	return unit, ok
+}
+
+// NormalizeUnit returns the registry code for code.
+func NormalizeUnit(code string) (string, error) {
+	unit, ok := LookupUnit(code)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownUnit, code)
+	}
+
+	return unit.Code, nil
+}
+
+// ConvertQuantity converts quantity from one unit to another. An empty from
+// unit means the quantity is already in the target unit.
+func ConvertQuantity(quantity float64, from, to string) (float64, error) {
+	if from == "" || strings.EqualFold(strings.TrimSpace(from), to) {
+		return quantity, nil
+	}
+
+	fromUnit, ok := LookupUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownUnit, from)
+	}
+	toUnit, ok := LookupUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownUnit, to)
+	}
+	if fromUnit.Dimension != toUnit.Dimension {
+		return 0, fmt.Errorf("%w: %s to %s", ErrIncompatibleUnits, fromUnit.Code, toUnit.Code)
+	}
+
+	return quantity * fromUnit.Factor / toUnit.Factor, nil
+}
+
+// UnitChangeRatio is what quantities stored in the current unit are
+// multiplied by when switching to unit. A current unit missing from the
+// registry is only relabelled, so its ratio is 1.
+func UnitChangeRatio(current, unit string) (float64, error) {
+	if _, known := LookupUnit(current); !known {
+		return 1, nil
+	}
+
+	return ConvertQuantity(1, current, unit)
+}
+
+// ChangeIngredientUnit switches the unit an ingredient is stocked in and
+// rescales everything stored in that unit: stock, threshold, cost, nutrition,
+// batches, recipes, lines of purchase orders not yet received and counts of
+// open stocktakes. Recorded stock movements keep the unit they were made in,
+// except the sale movements of orders that still hold their stock, which
+// RestoreOrderStock replays on cancellation. An ingredient whose current unit
+// is not in the registry is relabelled without rescaling.
+func ChangeIngredientUnit(tx orm.Query, ingredient models.Ingredients, unit string) error {
+	ratio, err := UnitChangeRatio(ingredient.Unit, unit)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE ingredients SET unit = ?, quantity = quantity * ?, threshold = threshold * ?, unit_cost = unit_cost / ?,
+		kcal = kcal / ?, protein = protein / ?, fat = fat / ?, carbs = carbs / ?, sodium = sodium / ? WHERE id = ?`,
+		unit, ratio, ratio, ratio, ratio, ratio, ratio, ratio, ratio, ingredient.ID); err != nil {
+		return err
+	}
+	if ratio == 1 {
+		return nil
+	}
+
+	if _, err := tx.Exec("UPDATE ingredient_batches SET quantity = quantity * ?, remaining = remaining * ?, unit_cost = unit_cost / ? WHERE ingredient_id = ?",
+		ratio, ratio, ratio, ingredient.ID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE product_ingredients SET amount_used = amount_used * ? WHERE ingredient_id = ?", ratio, ingredient.ID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE stock_movements SET change = change * ?
+		WHERE ingredient_id = ? AND type IN (?, ?) AND order_id IN (SELECT id FROM orders WHERE stock_deducted = ?)`,
+		ratio, ingredient.ID, StockMovementSale, StockMovementSaleCancel, true); err != nil {
+		return err
+	}
+	// Ordered lines were converted to the old unit when the order was saved
+	if _, err := tx.Exec(`UPDATE purchase_order_lines SET quantity = quantity * ?, unit_cost = unit_cost / ?
+		WHERE ingredient_id = ? AND purchase_order_id IN (SELECT id FROM purchase_orders WHERE status IN (?, ?))`,
+		ratio, ratio, ingredient.ID, PurchaseOrderDraft, PurchaseOrderSent); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE stocktake_lines SET expected_quantity = expected_quantity * ?, counted_quantity = counted_quantity * ?
+		WHERE ingredient_id = ? AND stocktake_id IN (SELECT id FROM stocktakes WHERE status = ?)`, ratio, ratio, ingredient.ID, StocktakeOpen)
+
+	return err
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goravel/framework/contracts/database/db"
+	"github.com/goravel/framework/contracts/database/orm"
+	"github.com/stretchr/testify/suite"
+
+	"goravel/app/models"
+)
+
+type UnitsTestSuite struct {
+	suite.Suite
+}
+
+func TestUnitsTestSuite(t *testing.T) {
+	suite.Run(t, new(UnitsTestSuite))
+}
+
+func (s *UnitsTestSuite) TestLookupUnitAcceptsAliases() {
+	unit, ok := LookupUnit(" Kilograms ")
+
+	s.True(ok)
+	s.Equal("kg", unit.Code)
+}
+
+func (s *UnitsTestSuite) TestUnitChangeRatio() {
+	ratio, err := UnitChangeRatio("kg", "g")
+	s.NoError(err)
+	s.InDelta(1000, ratio, 1e-9)
+
+	ratio, err = UnitChangeRatio("ml", "l")
+	s.NoError(err)
+	s.InDelta(0.001, ratio, 1e-12)
+}
+
+func (s *UnitsTestSuite) TestUnitChangeRatioRelabelsUnknownUnits() {
+	ratio, err := UnitChangeRatio("bag", "kg")
+
+	s.NoError(err)
+	s.Equal(1.0, ratio)
+}
+
+func (s *UnitsTestSuite) TestUnitChangeRatioRejectsOtherDimension() {
+	_, err := UnitChangeRatio("kg", "l")
+
+	s.Error(err)
+}
+
+func (s *UnitsTestSuite) TestChangeIngredientUnitRescalesOpenPurchaseOrderLines() {
+	tx := &execRecorder{}
+
+	s.NoError(ChangeIngredientUnit(tx, models.Ingredients{ID: 7, Unit: "kg"}, "g"))
+
+	values, ok := tx.values("UPDATE purchase_order_lines")
+	s.True(ok)
+	s.Equal([]any{1000.0, 1000.0, int64(7), PurchaseOrderDraft, PurchaseOrderSent}, values)
+	for _, table := range []string{"ingredients", "ingredient_batches", "product_ingredients", "stock_movements", "stocktake_lines"} {
+		_, ok := tx.values("UPDATE " + table + " ")
+		s.True(ok, table)
+	}
+}
+
+func (s *UnitsTestSuite) TestChangeIngredientUnitOnlyRelabelsUnknownUnits() {
+	tx := &execRecorder{}
+
+	s.NoError(ChangeIngredientUnit(tx, models.Ingredients{ID: 7, Unit: "bag"}, "kg"))
+
+	s.Len(tx.statements, 1)
+	_, ok := tx.values("UPDATE purchase_order_lines")
+	s.False(ok)
+}
+
+// execRecorder stands in for a transaction and keeps the statements run
+// through Exec.
+type execRecorder struct {
+	orm.Query
+	statements []string
+	arguments  [][]any
+}
+
+func (r *execRecorder) Exec(sql string, values ...any) (*db.Result, error) {
+	r.statements = append(r.statements, sql)
+	r.arguments = append(r.arguments, values)
+
+	return &db.Result{}, nil
+}
+
+func (r *execRecorder) values(prefix string) ([]any, bool) {
+	for index, statement := range r.statements {
+		if strings.HasPrefix(statement, prefix) {
+			return r.arguments[index], true
+		}
+	}
+
+	return nil, false
+}
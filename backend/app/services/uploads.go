@@ -0,0 +1,240 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goravel/framework/contracts/filesystem"
+	"github.com/goravel/framework/facades"
+	"github.com/goravel/framework/support/str"
+)
+
+const (
+	maxImageDimension = 8000
+	// maxImagePixels bounds the memory a decoded upload takes, 16 megapixels
+	// are 64MB as RGBA
+	maxImagePixels       = 16_000_000
+	defaultThumbnailSize = 600
+)
+
+var (
+	ErrImageTooLarge    = errors.New("image is too large")
+	ErrUnsupportedImage = errors.New("only JPEG, PNG and GIF images are allowed")
+	ErrImageUnreadable  = errors.New("image could not be read")
+)
+
+// allowedImageTypes maps the accepted content types to the stored extension.
+var allowedImageTypes = map[string]string{"image/jpeg": "jpg", "image/png": "png", "image/gif": "gif"}
+
+// UploadedImage describes a stored image and its resized copies.
+type UploadedImage struct {
+	Path       string            `json:"path"`
+	URL        string            `json:"url"`
+	MimeType   string            `json:"mime_type"`
+	Size       int64             `json:"size"`
+	Width      int               `json:"width"`
+	Height     int               `json:"height"`
+	Thumbnails map[string]string `json:"thumbnails"`
+}
+
+// ThumbnailURL is the URL stored on records that show a single picture, the
+// medium thumbnail when there is one.
+func (uploaded UploadedImage) ThumbnailURL() string {
+	if url, ok := uploaded.Thumbnails["medium"]; ok {
+		return url
+	}
+
+	return uploaded.URL
+}
+
+// MaxUploadSize is the largest accepted upload in bytes.
+func MaxUploadSize() int64 {
+	return int64(facades.Config().GetInt("filesystems.uploads.max_size", 5120)) * 1024
+}
+
+// thumbnailSizes returns the configured thumbnail names and their longest side.
+func thumbnailSizes() map[string]int {
+	sizes := map[string]int{}
+	if configured, ok := facades.Config().Get("filesystems.uploads.thumbnails").(map[string]any); ok {
+		for name, size := range configured {
+			if pixels, ok := size.(int); ok && pixels > 0 {
+				sizes[name] = pixels
+			}
+		}
+	}
+	if len(sizes) == 0 {
+		sizes["medium"] = defaultThumbnailSize
+	}
+
+	return sizes
+}
+
+// StoreImage validates an uploaded image, stores it on the default disk under
+// the uploads folder and writes a resized copy for every thumbnail size.
+// Files are checked by their content, not by the name the client sent.
+func StoreImage(file filesystem.File, folder string) (UploadedImage, error) {
+	var uploaded UploadedImage
+
+	size, err := file.Size()
+	if err != nil {
+		return uploaded, err
+	}
+	if size > MaxUploadSize() {
+		return uploaded, ErrImageTooLarge
+	}
+
+	data, err := os.ReadFile(file.File())
+	if err != nil {
+		return uploaded, err
+	}
+	mimeType := http.DetectContentType(data)
+	extension, ok := allowedImageTypes[mimeType]
+	if !ok {
+		return uploaded, ErrUnsupportedImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return uploaded, ErrImageUnreadable
+	}
+	if config.Width > maxImageDimension || config.Height > maxImageDimension || config.Width*config.Height > maxImagePixels {
+		return uploaded, ErrImageTooLarge
+	}
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return uploaded, ErrImageUnreadable
+	}
+
+	directory := path.Join(facades.Config().GetString("filesystems.uploads.folder", "uploads"), folder, time.Now().Format("2006/01"))
+	name := strings.ToLower(str.Random(32))
+	storage := facades.Storage()
+
+	uploaded = UploadedImage{
+		Path:       path.Join(directory, name+"."+extension),
+		MimeType:   mimeType,
+		Size:       size,
+		Width:      config.Width,
+		Height:     config.Height,
+		Thumbnails: map[string]string{},
+	}
+	if err := storage.Put(uploaded.Path, string(data)); err != nil {
+		return uploaded, err
+	}
+	uploaded.URL = storage.Url(uploaded.Path)
+
+	sizes := thumbnailSizes()
+	names := make([]string, 0, len(sizes))
+	for sizeName := range sizes {
+		names = append(names, sizeName)
+	}
+	sort.Strings(names)
+	for _, sizeName := range names {
+		// PNG and GIF keep transparency, everything else becomes JPEG
+		thumbExtension := "jpg"
+		var encoded bytes.Buffer
+		resized := resizeToFit(source, sizes[sizeName])
+		if mimeType == "image/jpeg" {
+			err = jpeg.Encode(&encoded, resized, &jpeg.Options{Quality: 85})
+		} else {
+			thumbExtension = "png"
+			err = png.Encode(&encoded, resized)
+		}
+		if err != nil {
+			return uploaded, err
+		}
+
+		thumbPath := path.Join(directory, fmt.Sprintf("%s_%s.%s", name, sizeName, thumbExtension))
+		if err := storage.Put(thumbPath, encoded.String()); err != nil {
+			return uploaded, err
+		}
+		uploaded.Thumbnails[sizeName] = storage.Url(thumbPath)
+	}
+
+	return uploaded, nil
+}
+
+// resizeToFit scales an image down so its longest side is at most maxSide,
+// averaging the source pixels that fall into each target pixel. Images that
+// are already small enough are returned unchanged.
+func resizeToFit(source image.Image, maxSide int) image.Image {
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return source
+	}
+
+	targetWidth, targetHeight := maxSide, height*maxSide/width
+	if height > width {
+		targetWidth, targetHeight = width*maxSide/height, maxSide
+	}
+	if targetWidth < 1 {
+		targetWidth = 1
+	}
+	if targetHeight < 1 {
+		targetHeight = 1
+	}
+
+	pixelAt := pixelReader(source)
+	target := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	for y := 0; y < targetHeight; y++ {
+		y0, y1 := y*height/targetHeight, (y+1)*height/targetHeight
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < targetWidth; x++ {
+			x0, x1 := x*width/targetWidth, (x+1)*width/targetWidth
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := pixelAt(bounds.Min.X+sx, bounds.Min.Y+sy)
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					count++
+				}
+			}
+
+			offset := target.PixOffset(x, y)
+			target.Pix[offset] = uint8(r / count >> 8)
+			target.Pix[offset+1] = uint8(g / count >> 8)
+			target.Pix[offset+2] = uint8(b / count >> 8)
+			target.Pix[offset+3] = uint8(a / count >> 8)
+		}
+	}
+
+	return target
+}
+
+// pixelReader returns the premultiplied colour of a pixel of source. The
+// formats the decoders produce are read directly instead of through At,
+// which allocates for every pixel.
+func pixelReader(source image.Image) func(x, y int) (r, g, b, a uint32) {
+	switch decoded := source.(type) {
+	case *image.YCbCr:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return decoded.YCbCrAt(x, y).RGBA() }
+	case *image.NRGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return decoded.NRGBAAt(x, y).RGBA() }
+	case *image.RGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return decoded.RGBAAt(x, y).RGBA() }
+	case *image.Gray:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return decoded.GrayAt(x, y).RGBA() }
+	default:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return source.At(x, y).RGBA() }
+	}
+}
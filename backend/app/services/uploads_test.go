@@ -0,0 +1,54 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type UploadsTestSuite struct {
+	suite.Suite
+}
+
+func TestUploadsTestSuite(t *testing.T) {
+	suite.Run(t, new(UploadsTestSuite))
+}
+
+func (s *UploadsTestSuite) TestResizeToFitKeepsSmallImages() {
+	source := image.NewRGBA(image.Rect(0, 0, 40, 30))
+
+	s.Same(source, resizeToFit(source, 600))
+}
+
+func (s *UploadsTestSuite) TestResizeToFitAveragesPixels() {
+	source := image.NewNRGBA(image.Rect(10, 10, 14, 12))
+	for y := 10; y < 12; y++ {
+		source.SetNRGBA(10, y, color.NRGBA{R: 200, A: 255})
+		source.SetNRGBA(11, y, color.NRGBA{R: 100, A: 255})
+		source.SetNRGBA(12, y, color.NRGBA{B: 60, A: 255})
+		source.SetNRGBA(13, y, color.NRGBA{B: 20, A: 255})
+	}
+
+	resized := resizeToFit(source, 2)
+
+	s.Equal(image.Rect(0, 0, 2, 1), resized.Bounds())
+	s.Equal(color.RGBA{R: 150, A: 255}, resized.At(0, 0))
+	s.Equal(color.RGBA{B: 40, A: 255}, resized.At(1, 0))
+}
+
+func (s *UploadsTestSuite) TestResizeToFitReadsDecodedJPEGs() {
+	source := image.NewYCbCr(image.Rect(0, 0, 30, 60), image.YCbCrSubsampleRatio420)
+	for index := range source.Y {
+		source.Y[index] = 255
+	}
+	for index := range source.Cb {
+		source.Cb[index], source.Cr[index] = 128, 128
+	}
+
+	resized := resizeToFit(source, 20)
+
+	s.Equal(image.Rect(0, 0, 10, 20), resized.Bounds())
+	s.Equal(color.RGBA{R: 255, G: 255, B: 255, A: 255}, resized.At(5, 10))
+}
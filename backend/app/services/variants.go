@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/goravel/framework/facades"
+
+	"goravel/app/models"
+)
+
+var (
+	ErrVariantRequired = errors.New("variant is required for this product")
+	ErrVariantNotFound = errors.New("variant not found")
+)
+
+// ResolveVariant checks the variant chosen for a product. Products with active
+// variants must be ordered through one of them; products without variants
+// must not name one. A nil variant means the product's own price and recipe.
+func ResolveVariant(productID int64, variantID *int64) (*models.ProductVariants, error) {
+	if variantID == nil || *variantID == 0 {
+		activeCount, err := facades.Orm().Query().Model(&models.ProductVariants{}).Where("product_id = ? AND status = ?", productID, true).Count()
+		if err != nil {
+			return nil, err
+		}
+		if activeCount > 0 {
+			return nil, ErrVariantRequired
+		}
+
+		return nil, nil
+	}
+
+	var variant models.ProductVariants
+	if err := facades.Orm().Query().Where("id = ? AND product_id = ? AND status = ?", *variantID, productID, true).First(&variant); err != nil {
+		return nil, err
+	}
+	if variant.ID == 0 {
+		return nil, ErrVariantNotFound
+	}
+
+	return &variant, nil
+}
+
+// RecipeFactor is how many recipe portions one unit of the variant uses.
+func RecipeFactor(variant *models.ProductVariants) float64 {
+	if variant == nil || variant.ID == 0 || variant.RecipeFactor <= 0 {
+		return 1
+	}
+
+	return variant.RecipeFactor
+}
+
+// CartItemUnitPrice is the price of one unit of a cart line including its
+// modifiers, loaded with its Product, Variant and Modifiers.Option.
+func CartItemUnitPrice(item models.CartItem) float64 {
+	if item.Variant != nil && item.Variant.ID != 0 {
+		return item.Variant.Price + ModifiersTotal(item)
+	}
+
+	return item.Product.Price + ModifiersTotal(item)
+}
+
+// OrderItemFactor is the recipe factor an order line was sold with, so later
+// edits or removal of the variant change neither its stock nor its margin.
+func OrderItemFactor(item models.OrderItems) float64 {
+	if item.RecipeFactor <= 0 {
+		return 1
+	}
+
+	return item.RecipeFactor
+}
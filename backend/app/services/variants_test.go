@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"goravel/app/models"
+)
+
+type VariantsTestSuite struct {
+	suite.Suite
+}
+
+func TestVariantsTestSuite(t *testing.T) {
+	suite.Run(t, new(VariantsTestSuite))
+}
+
+func (s *VariantsTestSuite) TestRecipeFactor() {
+	s.Equal(1.0, RecipeFactor(nil))
+	s.Equal(1.0, RecipeFactor(&models.ProductVariants{}))
+	s.Equal(1.0, RecipeFactor(&models.ProductVariants{ID: 1}))
+	s.Equal(1.5, RecipeFactor(&models.ProductVariants{ID: 1, RecipeFactor: 1.5}))
+}
+
+func (s *VariantsTestSuite) TestOrderItemFactorFallsBackToOne() {
+	s.Equal(1.0, OrderItemFactor(models.OrderItems{}))
+	s.Equal(0.5, OrderItemFactor(models.OrderItems{RecipeFactor: 0.5}))
+}
+
+func (s *VariantsTestSuite) TestCartItemUnitPrice() {
+	item := models.CartItem{
+		Product: models.Product{Price: 30000},
+		Modifiers: []models.CartItemModifiers{
+			{Option: models.ModifierOptions{PriceDelta: 5000}},
+			{Option: models.ModifierOptions{PriceDelta: 2000}},
+		},
+	}
+	s.Equal(37000.0, CartItemUnitPrice(item))
+
+	item.Variant = &models.ProductVariants{ID: 2, Price: 45000}
+	s.Equal(52000.0, CartItemUnitPrice(item))
+}
@@ -73,6 +73,12 @@ func init() {
 		// the language folders that are provided through your application.
 		"fallback_locale": "en",
 
+		// Supported Content Locales
+		//
+		// Product and category content can be translated into these locales.
+		// Requests pick one with ?lang= or the Accept-Language header.
+		"supported_locales": config.Env("APP_SUPPORTED_LOCALES", "vi,en"),
+
 		// Application Lang Path
 		//
 		// The path to the language files for the application. You may change
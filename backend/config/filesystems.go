@@ -26,6 +26,7 @@ func init() {
 			"local": map[string]any{
 				"driver": "local",
 				"root":   path.Storage("app"),
+				"url":    config.Env("APP_URL", "").(string) + "/files",
 			},
 			"public": map[string]any{
 				"driver": "local",
@@ -33,5 +34,19 @@ func init() {
 				"url":    config.Env("APP_URL", "").(string) + "/storage",
 			},
 		},
+
+		// Image Uploads
+		//
+		// Product thumbnails and voucher images are stored on the default disk
+		// under this folder. Files larger than max_size (in kilobytes) are
+		// rejected, and a resized copy is generated for each thumbnail size.
+		"uploads": map[string]any{
+			"folder":   "uploads",
+			"max_size": config.Env("UPLOAD_MAX_SIZE", 5120),
+			"thumbnails": map[string]any{
+				"small":  200,
+				"medium": 600,
+			},
+		},
 	})
 }
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"github.com/goravel/framework/facades"
+)
+
+func init() {
+	config := facades.Config()
+	config.Add("restaurant", map[string]any{
+		// Restaurant Timezone
+		//
+		// Menu availability schedules and demand forecasts are read in the
+		// restaurant's local time, independently of the application timezone.
+		"timezone": config.Env("RESTAURANT_TIMEZONE", "Asia/Ho_Chi_Minh"),
+	})
+}
@@ -15,6 +15,29 @@ func (kernel Kernel) Migrations() []schema.Migration {
 	return []schema.Migration{
 		&migrations.M20210101000001CreateUsersTable{},
 		&migrations.M20210101000002CreateJobsTable{},
+		&migrations.M20261018000001CreateIngredientsTable{},
+		&migrations.M20261018000002CreateStockMovementsTable{},
+		&migrations.M20261018000003AddOrderStockTrackingColumns{},
+		&migrations.M20261018000004CreateAdminNotificationsTable{},
+		&migrations.M20261018000005CreateSuppliersTable{},
+		&migrations.M20261018000006CreatePurchaseOrdersTable{},
+		&migrations.M20261018000007CreateStocktakesTable{},
+		&migrations.M20261018000008CreateIngredientBatchesTable{},
+		&migrations.M20261018000009CreateProductVariantsTable{},
+		&migrations.M20261018000010CreateModifierGroupsTable{},
+		&migrations.M20261018000011CreateCombosTable{},
+		&migrations.M20261018000012MakeProductsSoftDeletable{},
+		&migrations.M20261018000013CreateAvailabilitySchedulesTable{},
+		&migrations.M20261018000014CreateProductPriceHistoryTable{},
+		&migrations.M20261018000015CreateTagsTable{},
+		&migrations.M20261018000016CreateTranslationsTables{},
+		&migrations.M20261018000017AddHierarchyToCategoriesTable{},
+		&migrations.M20261018000018CreateProductNutritionTable{},
+		&migrations.M20261018000019CreateProductRecommendationsTable{},
+		&migrations.M20261018000020AllowGuestCarts{},
+		&migrations.M20261018000021WidenIngredientUnitCostScale{},
+		&migrations.M20261018000022AddRecipeFactorToOrderItems{},
+		&migrations.M20261018000023WidenPurchaseOrderLineUnitCostScale{},
 	}
 }
 
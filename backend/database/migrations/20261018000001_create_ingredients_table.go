@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000001CreateIngredientsTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000001CreateIngredientsTable) Signature() string {
+	return "20261018000001_create_ingredients_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000001CreateIngredientsTable) Up() error {
+	if !facades.Schema().HasTable("ingredients") {
+		if err := facades.Schema().Create("ingredients", func(table schema.Blueprint) {
+			table.ID()
+			table.String("name")
+			table.Decimal("quantity").Total(12).Places(3).Default(0)
+			table.String("unit", 20)
+			table.Decimal("threshold").Total(12).Places(3).Default(0)
+			table.Unique("name")
+		}); err != nil {
+			return err
+		}
+	}
+
+	if !facades.Schema().HasTable("product_ingredients") {
+		if err := facades.Schema().Create("product_ingredients", func(table schema.Blueprint) {
+			table.UnsignedBigInteger("product_id")
+			table.UnsignedBigInteger("ingredient_id")
+			table.Decimal("amount_used").Total(12).Places(3)
+			table.Primary("product_id", "ingredient_id")
+			table.Index("ingredient_id")
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000001CreateIngredientsTable) Down() error {
+	if err := facades.Schema().DropIfExists("product_ingredients"); err != nil {
+		return err
+	}
+
+	return facades.Schema().DropIfExists("ingredients")
+}
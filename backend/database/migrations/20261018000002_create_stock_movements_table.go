@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000002CreateStockMovementsTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000002CreateStockMovementsTable) Signature() string {
+	return "20261018000002_create_stock_movements_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000002CreateStockMovementsTable) Up() error {
+	return facades.Schema().Create("stock_movements", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("ingredient_id")
+		table.String("type", 30)
+		table.Decimal("change").Total(12).Places(3)
+		table.Decimal("quantity_after").Total(12).Places(3)
+		table.Text("reason").Nullable()
+		table.UnsignedBigInteger("user_id")
+		table.DateTimeTz("created_at").UseCurrent()
+		table.Index("ingredient_id")
+		table.Foreign("ingredient_id").References("id").On("ingredients")
+	})
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000002CreateStockMovementsTable) Down() error {
+	return facades.Schema().DropIfExists("stock_movements")
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000003AddOrderStockTrackingColumns struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000003AddOrderStockTrackingColumns) Signature() string {
+	return "20261018000003_add_order_stock_tracking_columns"
+}
+
+// Up Run the migrations.
+func (r *M20261018000003AddOrderStockTrackingColumns) Up() error {
+	if !facades.Schema().HasColumn("orders", "stock_deducted") {
+		if err := facades.Schema().Table("orders", func(table schema.Blueprint) {
+			table.Boolean("stock_deducted").Default(false)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return facades.Schema().Table("stock_movements", func(table schema.Blueprint) {
+		table.UnsignedBigInteger("order_id").Nullable()
+		table.Index("order_id")
+	})
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000003AddOrderStockTrackingColumns) Down() error {
+	if err := facades.Schema().DropColumns("stock_movements", []string{"order_id"}); err != nil {
+		return err
+	}
+
+	return facades.Schema().DropColumns("orders", []string{"stock_deducted"})
+}
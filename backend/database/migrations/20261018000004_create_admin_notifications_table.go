@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000004CreateAdminNotificationsTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000004CreateAdminNotificationsTable) Signature() string {
+	return "20261018000004_create_admin_notifications_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000004CreateAdminNotificationsTable) Up() error {
+	return facades.Schema().Create("admin_notifications", func(table schema.Blueprint) {
+		table.ID()
+		table.String("type", 30)
+		table.String("title")
+		table.Text("message").Nullable()
+		table.UnsignedBigInteger("ingredient_id").Nullable()
+		table.Timestamp("read_at").Nullable()
+		table.Timestamp("resolved_at").Nullable()
+		table.DateTimeTz("created_at").UseCurrent()
+		table.Index("type", "ingredient_id")
+	})
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000004CreateAdminNotificationsTable) Down() error {
+	return facades.Schema().DropIfExists("admin_notifications")
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000005CreateSuppliersTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000005CreateSuppliersTable) Signature() string {
+	return "20261018000005_create_suppliers_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000005CreateSuppliersTable) Up() error {
+	return facades.Schema().Create("suppliers", func(table schema.Blueprint) {
+		table.ID()
+		table.String("name")
+		table.String("contact_name", 100).Nullable()
+		table.String("phone", 20).Nullable()
+		table.String("email").Nullable()
+		table.Text("address").Nullable()
+		table.Text("note").Nullable()
+		table.Boolean("is_active").Default(true)
+		table.DateTimeTz("created_at").UseCurrent()
+	})
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000005CreateSuppliersTable) Down() error {
+	return facades.Schema().DropIfExists("suppliers")
+}
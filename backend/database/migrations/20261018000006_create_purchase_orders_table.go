@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000006CreatePurchaseOrdersTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000006CreatePurchaseOrdersTable) Signature() string {
+	return "20261018000006_create_purchase_orders_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000006CreatePurchaseOrdersTable) Up() error {
+	if err := facades.Schema().Create("purchase_orders", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("supplier_id")
+		table.String("status", 20).Default("draft")
+		table.Text("note").Nullable()
+		table.Decimal("total").Total(14).Places(2).Default(0)
+		table.UnsignedBigInteger("created_by")
+		table.Timestamp("sent_at").Nullable()
+		table.Timestamp("received_at").Nullable()
+		table.TimestampsTz()
+		table.Index("supplier_id")
+		table.Index("status")
+		table.Foreign("supplier_id").References("id").On("suppliers")
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("purchase_order_lines", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("purchase_order_id")
+		table.UnsignedBigInteger("ingredient_id")
+		table.Decimal("quantity").Total(12).Places(3)
+		table.Decimal("unit_cost").Total(14).Places(2)
+		table.Decimal("received_quantity").Total(12).Places(3).Default(0)
+		table.Index("purchase_order_id")
+		table.Index("ingredient_id")
+		table.Foreign("purchase_order_id").References("id").On("purchase_orders").CascadeOnDelete()
+		table.Foreign("ingredient_id").References("id").On("ingredients")
+	}); err != nil {
+		return err
+	}
+
+	if !facades.Schema().HasColumn("ingredients", "unit_cost") {
+		return facades.Schema().Table("ingredients", func(table schema.Blueprint) {
+			table.Decimal("unit_cost").Total(14).Places(2).Default(0)
+		})
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000006CreatePurchaseOrdersTable) Down() error {
+	if err := facades.Schema().DropColumns("ingredients", []string{"unit_cost"}); err != nil {
+		return err
+	}
+	if err := facades.Schema().DropIfExists("purchase_order_lines"); err != nil {
+		return err
+	}
+
+	return facades.Schema().DropIfExists("purchase_orders")
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000007CreateStocktakesTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000007CreateStocktakesTable) Signature() string {
+	return "20261018000007_create_stocktakes_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000007CreateStocktakesTable) Up() error {
+	if err := facades.Schema().Create("stocktakes", func(table schema.Blueprint) {
+		table.ID()
+		table.String("status", 20).Default("open")
+		table.Text("note").Nullable()
+		table.UnsignedBigInteger("opened_by")
+		table.UnsignedBigInteger("closed_by").Nullable()
+		table.Timestamp("closed_at").Nullable()
+		table.TimestampsTz()
+		table.Index("status")
+	}); err != nil {
+		return err
+	}
+
+	return facades.Schema().Create("stocktake_lines", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("stocktake_id")
+		table.UnsignedBigInteger("ingredient_id")
+		table.Decimal("expected_quantity").Total(12).Places(3)
+		table.Decimal("counted_quantity").Total(12).Places(3)
+		table.Decimal("unit_cost").Total(14).Places(2).Default(0)
+		table.UnsignedBigInteger("counted_by")
+		table.Timestamp("counted_at").Nullable()
+		table.Unique("stocktake_id", "ingredient_id")
+		table.Index("ingredient_id")
+		table.Foreign("stocktake_id").References("id").On("stocktakes").CascadeOnDelete()
+		table.Foreign("ingredient_id").References("id").On("ingredients")
+	})
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000007CreateStocktakesTable) Down() error {
+	if err := facades.Schema().DropIfExists("stocktake_lines"); err != nil {
+		return err
+	}
+
+	return facades.Schema().DropIfExists("stocktakes")
+}
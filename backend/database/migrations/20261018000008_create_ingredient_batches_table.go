@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000008CreateIngredientBatchesTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000008CreateIngredientBatchesTable) Signature() string {
+	return "20261018000008_create_ingredient_batches_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000008CreateIngredientBatchesTable) Up() error {
+	if err := facades.Schema().Create("ingredient_batches", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("ingredient_id")
+		table.Decimal("quantity").Total(12).Places(3)
+		table.Decimal("remaining").Total(12).Places(3)
+		table.Decimal("unit_cost").Total(14).Places(2).Default(0)
+		table.Timestamp("received_at")
+		table.Timestamp("expires_at").Nullable()
+		table.UnsignedBigInteger("purchase_order_id").Nullable()
+		table.DateTimeTz("created_at").UseCurrent()
+		table.Index("ingredient_id", "received_at")
+		table.Index("expires_at")
+		table.Foreign("ingredient_id").References("id").On("ingredients")
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Table("stock_movements", func(table schema.Blueprint) {
+		table.UnsignedBigInteger("batch_id").Nullable()
+		table.Index("batch_id")
+	}); err != nil {
+		return err
+	}
+
+	// Existing stock becomes a single batch per ingredient without an expiry date
+	_, err := facades.Orm().Query().Exec(`INSERT INTO ingredient_batches (ingredient_id, quantity, remaining, unit_cost, received_at)
+		SELECT id, quantity, quantity, unit_cost, NOW() FROM ingredients WHERE quantity > 0`)
+
+	return err
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000008CreateIngredientBatchesTable) Down() error {
+	if err := facades.Schema().DropColumns("stock_movements", []string{"batch_id"}); err != nil {
+		return err
+	}
+
+	return facades.Schema().DropIfExists("ingredient_batches")
+}
@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000009CreateProductVariantsTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000009CreateProductVariantsTable) Signature() string {
+	return "20261018000009_create_product_variants_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000009CreateProductVariantsTable) Up() error {
+	if err := facades.Schema().Create("product_variants", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("product_id")
+		table.String("name", 100)
+		table.String("sku", 64)
+		table.Decimal("price").Total(12).Places(2)
+		table.Decimal("recipe_factor").Total(8).Places(3).Default(1)
+		table.Boolean("is_default").Default(false)
+		table.Boolean("status").Default(true)
+		table.Integer("sort_order").Default(0)
+		table.TimestampsTz()
+		table.Unique("sku")
+		table.Index("product_id")
+	}); err != nil {
+		return err
+	}
+
+	if facades.Schema().HasTable("cart_items") && !facades.Schema().HasColumn("cart_items", "variant_id") {
+		if err := facades.Schema().Table("cart_items", func(table schema.Blueprint) {
+			table.UnsignedBigInteger("variant_id").Nullable()
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Order lines keep the variant name so history survives a deleted variant
+	if facades.Schema().HasTable("order_items") && !facades.Schema().HasColumn("order_items", "variant_id") {
+		return facades.Schema().Table("order_items", func(table schema.Blueprint) {
+			table.UnsignedBigInteger("variant_id").Nullable()
+			table.String("variant_name", 100).Nullable()
+			table.Index("variant_id")
+		})
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000009CreateProductVariantsTable) Down() error {
+	if err := facades.Schema().DropColumns("order_items", []string{"variant_id", "variant_name"}); err != nil {
+		return err
+	}
+	if err := facades.Schema().DropColumns("cart_items", []string{"variant_id"}); err != nil {
+		return err
+	}
+
+	return facades.Schema().DropIfExists("product_variants")
+}
@@ -0,0 +1,83 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000010CreateModifierGroupsTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000010CreateModifierGroupsTable) Signature() string {
+	return "20261018000010_create_modifier_groups_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000010CreateModifierGroupsTable) Up() error {
+	if err := facades.Schema().Create("modifier_groups", func(table schema.Blueprint) {
+		table.ID()
+		table.String("name", 100)
+		table.Integer("min_select").Default(0)
+		table.Integer("max_select").Default(0)
+		table.Boolean("required").Default(false)
+		table.TimestampsTz()
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("modifier_options", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("group_id")
+		table.String("name", 100)
+		table.Decimal("price_delta").Total(12).Places(2).Default(0)
+		table.Boolean("status").Default(true)
+		table.Integer("sort_order").Default(0)
+		table.Foreign("group_id").References("id").On("modifier_groups")
+		table.Index("group_id")
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("product_modifier_groups", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("product_id")
+		table.UnsignedBigInteger("modifier_group_id")
+		table.Integer("sort_order").Default(0)
+		table.Foreign("modifier_group_id").References("id").On("modifier_groups")
+		table.Unique("product_id", "modifier_group_id")
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("cart_item_modifiers", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("cart_item_id")
+		table.UnsignedBigInteger("modifier_option_id")
+		table.Foreign("modifier_option_id").References("id").On("modifier_options")
+		table.Index("cart_item_id")
+	}); err != nil {
+		return err
+	}
+
+	// Order lines keep names and prices rather than references, menus change
+	return facades.Schema().Create("order_item_modifiers", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("order_item_id")
+		table.UnsignedBigInteger("modifier_option_id")
+		table.String("group_name", 100)
+		table.String("option_name", 100)
+		table.Decimal("price_delta").Total(12).Places(2).Default(0)
+		table.Index("order_item_id")
+	})
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000010CreateModifierGroupsTable) Down() error {
+	for _, table := range []string{"order_item_modifiers", "cart_item_modifiers", "product_modifier_groups", "modifier_options", "modifier_groups"} {
+		if err := facades.Schema().DropIfExists(table); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
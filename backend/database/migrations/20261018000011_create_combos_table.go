@@ -0,0 +1,110 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000011CreateCombosTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000011CreateCombosTable) Signature() string {
+	return "20261018000011_create_combos_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000011CreateCombosTable) Up() error {
+	if err := facades.Schema().Create("combos", func(table schema.Blueprint) {
+		table.ID()
+		table.String("name", 150)
+		table.Text("description").Nullable()
+		table.String("thumbnail").Nullable()
+		table.Decimal("price").Total(12).Places(2)
+		table.Boolean("status").Default(true)
+		table.TimestampsTz()
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("combo_slots", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("combo_id")
+		table.String("name", 100)
+		table.Integer("quantity").Default(1)
+		table.Integer("sort_order").Default(0)
+		table.Foreign("combo_id").References("id").On("combos").CascadeOnDelete()
+		table.Index("combo_id")
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("combo_slot_options", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("slot_id")
+		table.UnsignedBigInteger("product_id")
+		table.UnsignedBigInteger("variant_id").Nullable()
+		table.Decimal("extra_price").Total(12).Places(2).Default(0)
+		table.Foreign("slot_id").References("id").On("combo_slots").CascadeOnDelete()
+		table.Index("slot_id")
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("cart_combos", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("cart_id")
+		table.UnsignedBigInteger("combo_id")
+		table.Integer("quantity")
+		table.Index("cart_id")
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("order_combos", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("order_id")
+		table.UnsignedBigInteger("combo_id")
+		table.String("name", 150)
+		table.Integer("quantity")
+		table.Decimal("unit_price").Total(12).Places(2)
+		table.Index("order_id")
+	}); err != nil {
+		return err
+	}
+
+	if facades.Schema().HasTable("cart_items") && !facades.Schema().HasColumn("cart_items", "cart_combo_id") {
+		if err := facades.Schema().Table("cart_items", func(table schema.Blueprint) {
+			table.UnsignedBigInteger("cart_combo_id").Nullable()
+			table.UnsignedBigInteger("combo_slot_option_id").Nullable()
+			table.Index("cart_combo_id")
+		}); err != nil {
+			return err
+		}
+	}
+
+	if facades.Schema().HasTable("order_items") && !facades.Schema().HasColumn("order_items", "order_combo_id") {
+		return facades.Schema().Table("order_items", func(table schema.Blueprint) {
+			table.UnsignedBigInteger("order_combo_id").Nullable()
+			table.Index("order_combo_id")
+		})
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000011CreateCombosTable) Down() error {
+	if err := facades.Schema().DropColumns("order_items", []string{"order_combo_id"}); err != nil {
+		return err
+	}
+	if err := facades.Schema().DropColumns("cart_items", []string{"cart_combo_id", "combo_slot_option_id"}); err != nil {
+		return err
+	}
+	for _, table := range []string{"order_combos", "cart_combos", "combo_slot_options", "combo_slots", "combos"} {
+		if err := facades.Schema().DropIfExists(table); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000012MakeProductsSoftDeletable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000012MakeProductsSoftDeletable) Signature() string {
+	return "20261018000012_make_products_soft_deletable"
+}
+
+// Up Run the migrations.
+func (r *M20261018000012MakeProductsSoftDeletable) Up() error {
+	if !facades.Schema().HasTable("products") {
+		return nil
+	}
+
+	if !facades.Schema().HasColumn("products", "deleted_at") {
+		return facades.Schema().Table("products", func(table schema.Blueprint) {
+			table.SoftDeletesTz()
+			table.Index("deleted_at")
+		})
+	}
+
+	// deleted_at used to be written as a zero time on every insert, which a
+	// soft delete scope would read as deleted
+	if _, err := facades.Orm().Query().Exec(`ALTER TABLE products ALTER COLUMN deleted_at DROP NOT NULL`); err != nil {
+		return err
+	}
+	_, err := facades.Orm().Query().Exec(`UPDATE products SET deleted_at = NULL WHERE deleted_at < '1900-01-01'`)
+
+	return err
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000012MakeProductsSoftDeletable) Down() error {
+	return nil
+}
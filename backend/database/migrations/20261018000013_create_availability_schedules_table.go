@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000013CreateAvailabilitySchedulesTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000013CreateAvailabilitySchedulesTable) Signature() string {
+	return "20261018000013_create_availability_schedules_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000013CreateAvailabilitySchedulesTable) Up() error {
+	if err := facades.Schema().Create("availability_schedules", func(table schema.Blueprint) {
+		table.ID()
+		table.String("name", 100)
+		table.TimestampsTz()
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("availability_periods", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("schedule_id")
+		table.SmallInteger("weekday")
+		table.String("start_time", 5)
+		table.String("end_time", 5)
+		table.Foreign("schedule_id").References("id").On("availability_schedules").CascadeOnDelete()
+		table.Index("schedule_id")
+	}); err != nil {
+		return err
+	}
+
+	for _, tableName := range []string{"products", "categories"} {
+		if facades.Schema().HasTable(tableName) && !facades.Schema().HasColumn(tableName, "availability_schedule_id") {
+			if err := facades.Schema().Table(tableName, func(table schema.Blueprint) {
+				table.UnsignedBigInteger("availability_schedule_id").Nullable()
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000013CreateAvailabilitySchedulesTable) Down() error {
+	for _, tableName := range []string{"products", "categories"} {
+		if err := facades.Schema().DropColumns(tableName, []string{"availability_schedule_id"}); err != nil {
+			return err
+		}
+	}
+	if err := facades.Schema().DropIfExists("availability_periods"); err != nil {
+		return err
+	}
+
+	return facades.Schema().DropIfExists("availability_schedules")
+}
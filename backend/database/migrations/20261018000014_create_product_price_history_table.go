@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000014CreateProductPriceHistoryTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000014CreateProductPriceHistoryTable) Signature() string {
+	return "20261018000014_create_product_price_history_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000014CreateProductPriceHistoryTable) Up() error {
+	if err := facades.Schema().Create("product_price_changes", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("product_id")
+		table.Decimal("price").Total(12).Places(2)
+		table.TimestampTz("effective_at")
+		table.String("status", 20).Default("pending")
+		table.Text("note").Nullable()
+		table.UnsignedBigInteger("created_by")
+		table.TimestampTz("applied_at").Nullable()
+		table.TimestampsTz()
+		table.Index("status", "effective_at")
+		table.Index("product_id")
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("product_price_history", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("product_id")
+		table.Decimal("price").Total(12).Places(2)
+		table.TimestampTz("started_at")
+		table.TimestampTz("ended_at").Nullable()
+		table.String("source", 20)
+		table.UnsignedBigInteger("changed_by").Nullable()
+		table.Index("product_id", "started_at")
+	}); err != nil {
+		return err
+	}
+
+	if !facades.Schema().HasTable("products") {
+		return nil
+	}
+
+	// Existing products start their history with the price they have today
+	_, err := facades.Orm().Query().Exec(`INSERT INTO product_price_history (product_id, price, started_at, source)
+		SELECT id, price, COALESCE(created_at, NOW()), 'initial' FROM products`)
+
+	return err
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000014CreateProductPriceHistoryTable) Down() error {
+	if err := facades.Schema().DropIfExists("product_price_history"); err != nil {
+		return err
+	}
+
+	return facades.Schema().DropIfExists("product_price_changes")
+}
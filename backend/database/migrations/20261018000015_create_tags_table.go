@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000015CreateTagsTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000015CreateTagsTable) Signature() string {
+	return "20261018000015_create_tags_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000015CreateTagsTable) Up() error {
+	if err := facades.Schema().Create("tags", func(table schema.Blueprint) {
+		table.ID()
+		table.String("name", 100)
+		table.String("slug", 100)
+		table.String("type", 20)
+		table.TimestampsTz()
+		table.Unique("slug")
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("product_tags", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("product_id")
+		table.UnsignedBigInteger("tag_id")
+		table.Foreign("tag_id").References("id").On("tags")
+		table.Unique("product_id", "tag_id")
+		table.Index("tag_id")
+	}); err != nil {
+		return err
+	}
+
+	if err := facades.Schema().Create("ingredient_tags", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("ingredient_id")
+		table.UnsignedBigInteger("tag_id")
+		table.Foreign("tag_id").References("id").On("tags")
+		table.Unique("ingredient_id", "tag_id")
+		table.Index("tag_id")
+	}); err != nil {
+		return err
+	}
+
+	// The labels guests ask about most, admins can add more later
+	_, err := facades.Orm().Query().Exec(`INSERT INTO tags (name, slug, type, created_at, updated_at) VALUES
+		('Vegetarian', 'vegetarian', 'dietary', NOW(), NOW()),
+		('Vegan', 'vegan', 'dietary', NOW(), NOW()),
+		('Spicy', 'spicy', 'dietary', NOW(), NOW()),
+		('Gluten', 'gluten', 'allergen', NOW(), NOW()),
+		('Peanuts', 'peanuts', 'allergen', NOW(), NOW()),
+		('Seafood', 'seafood', 'allergen', NOW(), NOW())`)
+
+	return err
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000015CreateTagsTable) Down() error {
+	for _, table := range []string{"ingredient_tags", "product_tags", "tags"} {
+		if err := facades.Schema().DropIfExists(table); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000016CreateTranslationsTables struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000016CreateTranslationsTables) Signature() string {
+	return "20261018000016_create_translations_tables"
+}
+
+// Up Run the migrations.
+func (r *M20261018000016CreateTranslationsTables) Up() error {
+	if err := facades.Schema().Create("product_translations", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("product_id")
+		table.String("locale", 10)
+		table.String("name")
+		table.Text("description").Nullable()
+		table.TimestampsTz()
+		table.Unique("product_id", "locale")
+	}); err != nil {
+		return err
+	}
+
+	return facades.Schema().Create("category_translations", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("category_id")
+		table.String("locale", 10)
+		table.String("name")
+		table.TimestampsTz()
+		table.Unique("category_id", "locale")
+	})
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000016CreateTranslationsTables) Down() error {
+	if err := facades.Schema().DropIfExists("category_translations"); err != nil {
+		return err
+	}
+
+	return facades.Schema().DropIfExists("product_translations")
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000017AddHierarchyToCategoriesTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000017AddHierarchyToCategoriesTable) Signature() string {
+	return "20261018000017_add_hierarchy_to_categories_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000017AddHierarchyToCategoriesTable) Up() error {
+	if !facades.Schema().HasTable("categories") {
+		return nil
+	}
+
+	if !facades.Schema().HasColumn("categories", "parent_id") {
+		if err := facades.Schema().Table("categories", func(table schema.Blueprint) {
+			table.UnsignedBigInteger("parent_id").Nullable()
+			table.Integer("sort_order").Default(0)
+			table.Boolean("status").Default(true)
+			table.String("icon").Default("")
+			table.Index("parent_id")
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Existing categories keep the order they were created in
+	_, err := facades.Orm().Query().Exec(`UPDATE categories SET sort_order = ordered.position
+		FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY id) - 1 AS position FROM categories) ordered
+		WHERE categories.id = ordered.id`)
+
+	return err
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000017AddHierarchyToCategoriesTable) Down() error {
+	return facades.Schema().DropColumns("categories", []string{"parent_id", "sort_order", "status", "icon"})
+}
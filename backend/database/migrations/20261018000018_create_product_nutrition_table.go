@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000018CreateProductNutritionTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000018CreateProductNutritionTable) Signature() string {
+	return "20261018000018_create_product_nutrition_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000018CreateProductNutritionTable) Up() error {
+	if facades.Schema().HasTable("ingredients") && !facades.Schema().HasColumn("ingredients", "has_nutrition") {
+		if err := facades.Schema().Table("ingredients", func(table schema.Blueprint) {
+			table.Boolean("has_nutrition").Default(false)
+			table.Decimal("kcal").Total(12).Places(4).Default(0)
+			table.Decimal("protein").Total(12).Places(4).Default(0)
+			table.Decimal("fat").Total(12).Places(4).Default(0)
+			table.Decimal("carbs").Total(12).Places(4).Default(0)
+			table.Decimal("sodium").Total(12).Places(4).Default(0)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return facades.Schema().Create("product_nutrition", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("product_id")
+		table.Decimal("kcal").Total(12).Places(2).Default(0)
+		table.Decimal("protein").Total(12).Places(2).Default(0)
+		table.Decimal("fat").Total(12).Places(2).Default(0)
+		table.Decimal("carbs").Total(12).Places(2).Default(0)
+		table.Decimal("sodium").Total(12).Places(2).Default(0)
+		table.Boolean("complete").Default(false)
+		table.Integer("missing_ingredients").Default(0)
+		table.TimestampTz("updated_at").Nullable()
+		table.Unique("product_id")
+	})
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000018CreateProductNutritionTable) Down() error {
+	if err := facades.Schema().DropIfExists("product_nutrition"); err != nil {
+		return err
+	}
+
+	return facades.Schema().DropColumns("ingredients", []string{"has_nutrition", "kcal", "protein", "fat", "carbs", "sodium"})
+}
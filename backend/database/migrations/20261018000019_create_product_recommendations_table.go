@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000019CreateProductRecommendationsTable struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000019CreateProductRecommendationsTable) Signature() string {
+	return "20261018000019_create_product_recommendations_table"
+}
+
+// Up Run the migrations.
+func (r *M20261018000019CreateProductRecommendationsTable) Up() error {
+	return facades.Schema().Create("product_recommendations", func(table schema.Blueprint) {
+		table.ID()
+		table.UnsignedBigInteger("product_id")
+		table.UnsignedBigInteger("recommended_product_id")
+		table.Integer("orders")
+		table.Double("confidence")
+		table.Double("lift")
+		table.Integer("rank")
+		table.TimestampTz("computed_at")
+		table.Index("product_id", "rank")
+	})
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000019CreateProductRecommendationsTable) Down() error {
+	return facades.Schema().DropIfExists("product_recommendations")
+}
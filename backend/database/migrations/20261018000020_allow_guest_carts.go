@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000020AllowGuestCarts struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000020AllowGuestCarts) Signature() string {
+	return "20261018000020_allow_guest_carts"
+}
+
+// Up Run the migrations.
+func (r *M20261018000020AllowGuestCarts) Up() error {
+	if !facades.Schema().HasTable("carts") {
+		return nil
+	}
+
+	// Guest carts belong to no user until they are merged on login
+	_, err := facades.Orm().Query().Exec(`ALTER TABLE carts ALTER COLUMN user_id DROP NOT NULL`)
+
+	return err
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000020AllowGuestCarts) Down() error {
+	if !facades.Schema().HasTable("carts") {
+		return nil
+	}
+
+	_, err := facades.Orm().Query().Exec(`DELETE FROM carts WHERE user_id IS NULL`)
+
+	return err
+}
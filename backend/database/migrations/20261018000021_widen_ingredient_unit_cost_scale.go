@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000021WidenIngredientUnitCostScale struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000021WidenIngredientUnitCostScale) Signature() string {
+	return "20261018000021_widen_ingredient_unit_cost_scale"
+}
+
+// Up Run the migrations.
+func (r *M20261018000021WidenIngredientUnitCostScale) Up() error {
+	// The cost per gram or millilitre of most ingredients is well below one
+	// cent, two decimals would round it to zero after a kg to g change
+	for _, table := range []string{"ingredients", "ingredient_batches"} {
+		if !facades.Schema().HasColumn(table, "unit_cost") {
+			continue
+		}
+		if _, err := facades.Orm().Query().Exec(`ALTER TABLE ` + table + ` ALTER COLUMN unit_cost TYPE decimal(18,6)`); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000021WidenIngredientUnitCostScale) Down() error {
+	for _, table := range []string{"ingredients", "ingredient_batches"} {
+		if !facades.Schema().HasColumn(table, "unit_cost") {
+			continue
+		}
+		if _, err := facades.Orm().Query().Exec(`ALTER TABLE ` + table + ` ALTER COLUMN unit_cost TYPE decimal(14,2)`); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
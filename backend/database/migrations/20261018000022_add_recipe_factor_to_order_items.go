@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/contracts/database/schema"
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000022AddRecipeFactorToOrderItems struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000022AddRecipeFactorToOrderItems) Signature() string {
+	return "20261018000022_add_recipe_factor_to_order_items"
+}
+
+// Up Run the migrations.
+func (r *M20261018000022AddRecipeFactorToOrderItems) Up() error {
+	if !facades.Schema().HasTable("order_items") || facades.Schema().HasColumn("order_items", "recipe_factor") {
+		return nil
+	}
+
+	// Order lines keep the recipe factor they were sold with, like the variant name
+	if err := facades.Schema().Table("order_items", func(table schema.Blueprint) {
+		table.Decimal("recipe_factor").Total(8).Places(3).Default(1)
+	}); err != nil {
+		return err
+	}
+	_, err := facades.Orm().Query().Exec(`UPDATE order_items SET recipe_factor = product_variants.recipe_factor
+		FROM product_variants WHERE product_variants.id = order_items.variant_id AND product_variants.recipe_factor > 0`)
+
+	return err
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000022AddRecipeFactorToOrderItems) Down() error {
+	return facades.Schema().DropColumns("order_items", []string{"recipe_factor"})
+}
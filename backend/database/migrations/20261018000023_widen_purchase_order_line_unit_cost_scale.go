@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/goravel/framework/facades"
+)
+
+type M20261018000023WidenPurchaseOrderLineUnitCostScale struct{}
+
+// Signature The unique signature for the migration.
+func (r *M20261018000023WidenPurchaseOrderLineUnitCostScale) Signature() string {
+	return "20261018000023_widen_purchase_order_line_unit_cost_scale"
+}
+
+// Up Run the migrations.
+func (r *M20261018000023WidenPurchaseOrderLineUnitCostScale) Up() error {
+	// Open order lines are rescaled with their ingredient's unit
+	if !facades.Schema().HasColumn("purchase_order_lines", "unit_cost") {
+		return nil
+	}
+	_, err := facades.Orm().Query().Exec(`ALTER TABLE purchase_order_lines ALTER COLUMN unit_cost TYPE decimal(18,6)`)
+
+	return err
+}
+
+// Down Reverse the migrations.
+func (r *M20261018000023WidenPurchaseOrderLineUnitCostScale) Down() error {
+	if !facades.Schema().HasColumn("purchase_order_lines", "unit_cost") {
+		return nil
+	}
+	_, err := facades.Orm().Query().Exec(`ALTER TABLE purchase_order_lines ALTER COLUMN unit_cost TYPE decimal(14,2)`)
+
+	return err
+}
@@ -15,6 +15,7 @@ func Api() {
 	// Categories routes - protected routes need auth
 	facades.Route().Get("/categories", categoryController.GetAll)
 	facades.Route().Middleware(middleware.Admin()).Post("/categories", categoryController.Create)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/categories/reorder", categoryController.Reorder)
 	facades.Route().Middleware(middleware.Admin()).Put("/categories/{id}", categoryController.Update)
 	facades.Route().Middleware(middleware.Admin()).Delete("/categories/{id}", categoryController.Delete)
 
@@ -23,17 +24,83 @@ func Api() {
 	facades.Route().Middleware(middleware.Admin()).Post("/products", productController.Create)
 	facades.Route().Get("/products", productController.GetAll)
 	facades.Route().Get("/products/{id}", productController.GetById)
+	facades.Route().Get("/products/{id}/recommendations", productController.GetRecommendations)
 	facades.Route().Middleware(middleware.Admin()).Delete("/products/{id}", productController.Remove)
 	facades.Route().Middleware(middleware.Admin()).Put("/products/{id}", productController.Update)
 	facades.Route().Middleware(middleware.Admin()).Post("/products/add", productController.AddProducts)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/products/trash", productController.GetTrash)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/products/{id}/restore", productController.Restore)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/products/{id}/recipe", productController.GetRecipe)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/products/{id}/recipe", productController.UpdateRecipe)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/products/{id}/price-history", productController.GetPriceHistory)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/products/{id}/price-changes", productController.GetPriceChanges)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/products/{id}/price-changes", productController.SchedulePrice)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/product-price-changes/{id}/cancel", productController.CancelPriceChange)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/products/{id}/variants", productController.GetVariants)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/products/{id}/variants", productController.CreateVariant)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/product-variants/{id}", productController.UpdateVariant)
+	facades.Route().Middleware(middleware.Admin()).Delete("/admin/product-variants/{id}", productController.DeleteVariant)
+
+	// Product and category translation routes
+	translationController := controllers.TranslationController{}
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/products/{id}/translations", translationController.GetProductTranslations)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/products/{id}/translations", translationController.SetProductTranslations)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/categories/{id}/translations", translationController.GetCategoryTranslations)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/categories/{id}/translations", translationController.SetCategoryTranslations)
+
+	// Menu import and export routes
+	menuController := controllers.MenuController{}
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/products/import", menuController.Import)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/products/export", menuController.Export)
+
+	// Product modifier routes
+	modifierController := controllers.ModifierController{}
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/modifier-groups", modifierController.GetAll)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/modifier-groups", modifierController.Create)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/modifier-groups/{id}", modifierController.Update)
+	facades.Route().Middleware(middleware.Admin()).Delete("/admin/modifier-groups/{id}", modifierController.Delete)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/modifier-groups/{id}/options", modifierController.CreateOption)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/modifier-options/{id}", modifierController.UpdateOption)
+	facades.Route().Middleware(middleware.Admin()).Delete("/admin/modifier-options/{id}", modifierController.DeleteOption)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/products/{id}/modifier-groups", modifierController.SetProductGroups)
+
+	// Dietary and allergen tag routes
+	tagController := controllers.TagController{}
+	facades.Route().Get("/tags", tagController.GetAll)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/tags", tagController.Create)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/tags/{id}", tagController.Update)
+	facades.Route().Middleware(middleware.Admin()).Delete("/admin/tags/{id}", tagController.Delete)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/products/{id}/tags", tagController.SetProductTags)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/ingredients/{id}/allergens", tagController.SetIngredientAllergens)
+
+	// Menu availability schedule routes
+	scheduleController := controllers.ScheduleController{}
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/schedules", scheduleController.GetAll)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/schedules", scheduleController.Create)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/schedules/{id}", scheduleController.Update)
+	facades.Route().Middleware(middleware.Admin()).Delete("/admin/schedules/{id}", scheduleController.Delete)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/products/{id}/schedule", scheduleController.AssignProduct)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/categories/{id}/schedule", scheduleController.AssignCategory)
+
+	// Combo routes
+	comboController := controllers.ComboController{}
+	facades.Route().Get("/combos", comboController.GetAll)
+	facades.Route().Get("/combos/{id}", comboController.GetById)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/combos", comboController.Create)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/combos/{id}", comboController.Update)
+	facades.Route().Middleware(middleware.Admin()).Delete("/admin/combos/{id}", comboController.Delete)
 
 	// Cart routes
 	cartController := controllers.CartController{}
-	facades.Route().Middleware(middleware.Auth()).Post("/cart/init", cartController.InitCart)
-	facades.Route().Middleware(middleware.Auth()).Get("/cart", cartController.GetCartByUserID)
-	facades.Route().Middleware(middleware.Auth()).Post("/cart/add-item", cartController.AddItemToCart)
-	facades.Route().Middleware(middleware.Auth()).Put("/cart/update-item", cartController.UpdateCartItem)
-	facades.Route().Middleware(middleware.Auth()).Delete("/cart/remove-item/:item_id", cartController.RemoveItemFromCart)
+	facades.Route().Middleware(middleware.OptionalAuth()).Post("/cart/init", cartController.InitCart)
+	facades.Route().Middleware(middleware.OptionalAuth()).Get("/cart", cartController.GetCartByUserID)
+	facades.Route().Middleware(middleware.OptionalAuth()).Get("/cart/recommendations", cartController.GetRecommendations)
+	facades.Route().Middleware(middleware.OptionalAuth()).Post("/cart/add-item", cartController.AddItemToCart)
+	facades.Route().Middleware(middleware.OptionalAuth()).Put("/cart/update-item", cartController.UpdateCartItem)
+	facades.Route().Middleware(middleware.OptionalAuth()).Delete("/cart/remove-item/:item_id", cartController.RemoveItemFromCart)
+	facades.Route().Middleware(middleware.OptionalAuth()).Post("/cart/add-combo", cartController.AddComboToCart)
+	facades.Route().Middleware(middleware.OptionalAuth()).Put("/cart/update-combo", cartController.UpdateCartCombo)
+	facades.Route().Middleware(middleware.OptionalAuth()).Delete("/cart/remove-combo/:cart_combo_id", cartController.RemoveComboFromCart)
 
 	// Voucher routes
 	voucherController := controllers.VoucherController{}
@@ -68,6 +135,63 @@ func Api() {
 	// Sales reports routes
 	facades.Route().Middleware(middleware.Admin()).Get("/reservations-orders", orderController.GetSalesReport)
 	facades.Route().Middleware(middleware.Admin()).Get("/admin/revenue-stats", orderController.GetRevenueStats)
+	reportController := controllers.ReportController{}
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/reports/product-costs", reportController.GetProductCosts)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/reports/product-margins", reportController.GetProductMargins)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/reports/price-comparison", reportController.GetPriceComparison)
+
+	// Ingredient inventory routes
+	ingredientController := controllers.IngredientController{}
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/ingredients", ingredientController.GetAll)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/ingredients", ingredientController.Create)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/ingredients/{id}", ingredientController.GetById)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/ingredients/{id}", ingredientController.Update)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/ingredients/{id}/nutrition", ingredientController.UpdateNutrition)
+	facades.Route().Middleware(middleware.Admin()).Delete("/admin/ingredients/{id}", ingredientController.Delete)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/ingredients/{id}/adjust", ingredientController.AdjustStock)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/ingredients/{id}/movements", ingredientController.GetMovements)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/ingredients/{id}/batches", ingredientController.GetBatches)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/ingredient-batches/expiring", ingredientController.GetExpiring)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/units", ingredientController.GetUnits)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/inventory/forecast", ingredientController.GetForecast)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/ingredient-batches/{id}/discard", ingredientController.DiscardBatch)
+
+	// Stocktake routes
+	stocktakeController := controllers.StocktakeController{}
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/stocktakes", stocktakeController.GetAll)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/stocktakes", stocktakeController.Create)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/stocktakes/{id}", stocktakeController.GetById)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/stocktakes/{id}/counts", stocktakeController.RecordCounts)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/stocktakes/{id}/variance", stocktakeController.GetVariance)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/stocktakes/{id}/close", stocktakeController.Close)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/stocktakes/{id}/cancel", stocktakeController.Cancel)
+
+	// Supplier and purchase order routes
+	supplierController := controllers.SupplierController{}
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/suppliers", supplierController.GetAll)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/suppliers", supplierController.Create)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/suppliers/{id}", supplierController.GetById)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/suppliers/{id}", supplierController.Update)
+	facades.Route().Middleware(middleware.Admin()).Delete("/admin/suppliers/{id}", supplierController.Delete)
+	purchaseOrderController := controllers.PurchaseOrderController{}
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/purchase-orders", purchaseOrderController.GetAll)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/purchase-orders", purchaseOrderController.Create)
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/purchase-orders/{id}", purchaseOrderController.GetById)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/purchase-orders/{id}", purchaseOrderController.Update)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/purchase-orders/{id}/send", purchaseOrderController.Send)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/purchase-orders/{id}/receive", purchaseOrderController.Receive)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/purchase-orders/{id}/cancel", purchaseOrderController.Cancel)
+
+	// Image upload routes
+	uploadController := controllers.UploadController{}
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/uploads/images", uploadController.UploadImage)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/products/{id}/thumbnail", uploadController.UploadProductThumbnail)
+	facades.Route().Middleware(middleware.Admin()).Post("/admin/vouchers/{id}/image", uploadController.UploadVoucherImage)
+
+	// Admin notification routes
+	notificationController := controllers.NotificationController{}
+	facades.Route().Middleware(middleware.Admin()).Get("/admin/notifications", notificationController.GetAll)
+	facades.Route().Middleware(middleware.Admin()).Put("/admin/notifications/{id}/read", notificationController.MarkAsRead)
 
 	// User vouchers routes
 	facades.Route().Middleware(middleware.Auth()).Get("/user/vouchers", voucherController.GetUserVouchers)
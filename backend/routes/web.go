@@ -4,6 +4,7 @@ import (
 	"github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/facades"
 	"github.com/goravel/framework/support"
+	"github.com/goravel/framework/support/path"
 )
 
 func Web() {
@@ -12,4 +13,10 @@ func Web() {
 			"version": support.Version,
 		})
 	})
+
+	// Uploaded images, served from the uploads folder of the local disk and
+	// from the public disk
+	uploadsFolder := facades.Config().GetString("filesystems.uploads.folder", "uploads")
+	facades.Route().Static("files/"+uploadsFolder, path.Storage("app", uploadsFolder))
+	facades.Route().Static("storage", path.Storage("app/public"))
 }